@@ -2,93 +2,206 @@ package main
 
 import "fmt"
 
-type Node struct {
-	data interface{}
-	next *Node
+type Node[T any] struct {
+	data T
+	next *Node[T]
 }
 
-type CircularLinkedList struct {
-	head   *Node
+// CircularLinkedList 单向循环链表。只保存尾节点，头节点就是 tail.next，
+// 这样在头部和尾部插入都是 O(1)
+type CircularLinkedList[T any] struct {
+	tail   *Node[T]
 	length int
 }
 
-func (list *CircularLinkedList) Append(data interface{}) {
-	node := &Node{data, nil}
-	if list.head == nil {
+func (list *CircularLinkedList[T]) head() *Node[T] {
+	if list.tail == nil {
+		return nil
+	}
+	return list.tail.next
+}
+
+// 在 prev 之后插入节点，prev 为 nil 时链表必须为空
+func (list *CircularLinkedList[T]) insertAfter(prev *Node[T], data T) *Node[T] {
+	node := &Node[T]{data, nil}
+	if prev == nil {
 		node.next = node
-		list.head = node
+		list.tail = node
 	} else {
-		cur := list.head
-		for cur.next != list.head {
-			cur = cur.next
-		}
-		cur.next = node
-		node.next = list.head
+		node.next = prev.next
+		prev.next = node
 	}
 	list.length++
+	return node
 }
 
-func (list *CircularLinkedList) Prepend(data interface{}) {
-	node := &Node{data, nil}
-	if list.head == nil {
-		node.next = node
+// 删除 prev 之后的节点
+func (list *CircularLinkedList[T]) removeAfter(prev *Node[T]) *Node[T] {
+	node := prev.next
+	if node == prev {
+		list.tail = nil
 	} else {
-		cur := list.head
-		for cur.next != list.head {
-			cur = cur.next
+		prev.next = node.next
+		if node == list.tail {
+			list.tail = prev
 		}
-		cur.next = node
-		node.next = list.head
-		list.head = node
 	}
-	list.length++
+	list.length--
+	return node
+}
+
+func (list *CircularLinkedList[T]) Append(data T) {
+	list.tail = list.insertAfter(list.tail, data)
 }
 
-func (list *CircularLinkedList) Remove(index int) error {
+func (list *CircularLinkedList[T]) Prepend(data T) {
+	list.insertAfter(list.tail, data)
+}
+
+func (list *CircularLinkedList[T]) Remove(index int) error {
 	if index < 0 || index >= list.length {
 		return fmt.Errorf("Index out of range")
 	}
-	if index == 0 {
-		cur := list.head
-		for cur.next != list.head {
-			cur = cur.next
-		}
-		cur.next = list.head.next
-		list.head = list.head.next
-	} else {
-		cur := list.head
-		for i := 0; i < index-1; i++ {
-			cur = cur.next
-		}
-		cur.next = cur.next.next
+	prev := list.tail
+	for i := 0; i < index; i++ {
+		prev = prev.next
 	}
-	list.length--
+	list.removeAfter(prev)
 	return nil
 }
 
-func (list *CircularLinkedList) Traverse() []interface{} {
-	var result []interface{}
-	if list.head == nil {
+func (list *CircularLinkedList[T]) Len() int {
+	return list.length
+}
+
+func (list *CircularLinkedList[T]) Traverse() []T {
+	var result []T
+	if list.tail == nil {
 		return result
 	}
-	cur := list.head
+	cur := list.head()
 	for {
 		result = append(result, cur.data)
 		cur = cur.next
-		if cur == list.head {
+		if cur == list.head() {
 			break
 		}
 	}
 	return result
 }
 
+// Cursor 指向循环链表中某个节点的游标。
+// 游标保存的是当前节点的前驱，因此在单向链表上也能 O(1) 删除当前节点。
+// 通过其它途径修改链表（包括另一个游标）后，游标可能失效
+type Cursor[T any] struct {
+	list *CircularLinkedList[T]
+	prev *Node[T] // 当前节点的前驱，链表为空时为 nil
+}
+
+// Cursor 返回指向头节点的游标
+func (list *CircularLinkedList[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{list: list, prev: list.tail}
+}
+
+// 游标创建时链表为空，之后有了元素则指向头节点
+func (c *Cursor[T]) sync() {
+	if c.prev == nil {
+		c.prev = c.list.tail
+	}
+}
+
+// Value 返回当前节点的值，链表为空时返回 false
+func (c *Cursor[T]) Value() (T, bool) {
+	c.sync()
+	if c.prev == nil {
+		var zero T
+		return zero, false
+	}
+	return c.prev.next.data, true
+}
+
+// Next 移动到下一个节点，尾节点的下一个是头节点
+func (c *Cursor[T]) Next() {
+	c.sync()
+	if c.prev != nil {
+		c.prev = c.prev.next
+	}
+}
+
+// AtHead 当前节点是否为头节点
+func (c *Cursor[T]) AtHead() bool {
+	c.sync()
+	return c.prev != nil && c.prev == c.list.tail
+}
+
+// Remove 删除当前节点并返回它的值，游标移到下一个节点
+func (c *Cursor[T]) Remove() (T, error) {
+	c.sync()
+	if c.prev == nil {
+		var zero T
+		return zero, fmt.Errorf("List is empty")
+	}
+	node := c.list.removeAfter(c.prev)
+	if c.list.tail == nil {
+		c.prev = nil
+	}
+	return node.data, nil
+}
+
+// InsertAfter 在当前节点之后插入元素，游标位置不变；链表为空时新元素成为当前节点
+func (c *Cursor[T]) InsertAfter(data T) {
+	c.sync()
+	if c.prev == nil {
+		c.list.insertAfter(nil, data)
+		c.prev = c.list.tail
+		return
+	}
+	cur := c.prev.next
+	node := c.list.insertAfter(cur, data)
+	if cur == c.list.tail {
+		c.list.tail = node
+	}
+}
+
 func main() {
-	list := CircularLinkedList{}
+	list := CircularLinkedList[int]{}
 	list.Append(1)
 	list.Append(2)
 	list.Append(3)
 	list.Prepend(0)
-	fmt.Println(list.Traverse())
+	fmt.Println(list.Traverse()) // [0 1 2 3]
 	list.Remove(2)
-	fmt.Println(list.Traverse())
+	fmt.Println(list.Traverse()) // [0 1 3]
+
+	empty := CircularLinkedList[int]{}
+	empty.Prepend(1)
+	empty.Prepend(0)
+	fmt.Println(empty.Traverse()) // [0 1]
+
+	c := list.Cursor()
+	c.Next()
+	c.InsertAfter(2)
+	v, _ := c.Remove()
+	fmt.Println(v, list.Traverse()) // 1 [0 2 3]
+
+	// 加权轮询：权重 5:1:1，长期来看 a 被选中的次数是 b、c 的 5 倍，且分散在各轮之间
+	wrr := NewWeightedRoundRobin[string]()
+	wrr.Add("a", 5)
+	wrr.Add("b", 1)
+	wrr.Add("c", 1)
+	for i := 0; i < 14; i++ {
+		s, _ := wrr.Next()
+		fmt.Print(s, " ")
+	}
+	fmt.Println() // a b c a a a a a b c a a a a
+	wrr.Remove(func(s string) bool { return s == "a" })
+	for i := 0; i < 4; i++ {
+		s, _ := wrr.Next()
+		fmt.Print(s, " ")
+	}
+	fmt.Println() // b c b c
+
+	order, survivor := Josephus(7, 3)
+	fmt.Println("出列顺序：", order, "幸存者：", survivor) // [3 6 2 7 5 1] 4
+	fmt.Println("递推公式：", JosephusSurvivor(7, 3))  // 4
 }
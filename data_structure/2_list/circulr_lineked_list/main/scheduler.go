@@ -0,0 +1,113 @@
+package main
+
+import "fmt"
+
+// 基于循环链表游标的加权轮询调度器（interleaved weighted round-robin）。
+// 游标沿环不停前进，每绕环一圈称为一轮，第 r 轮只选中权重不小于 r 的成员，
+// 最大权重轮之后重新从第 1 轮开始。这样权重为 w 的成员每个周期被选中 w 次，
+// 而且高权重成员的请求分散在各轮之间，不会连续占满。
+
+type wrrEntry[T any] struct {
+	value  T
+	weight int
+}
+
+type WeightedRoundRobin[T any] struct {
+	ring      CircularLinkedList[*wrrEntry[T]]
+	cursor    *Cursor[*wrrEntry[T]]
+	round     int // 当前轮次，从 1 开始
+	maxWeight int
+}
+
+func NewWeightedRoundRobin[T any]() *WeightedRoundRobin[T] {
+	s := &WeightedRoundRobin[T]{round: 1}
+	s.cursor = s.ring.Cursor()
+	return s
+}
+
+// Add 加入一个权重为 weight 的成员，权重至少为 1
+func (s *WeightedRoundRobin[T]) Add(value T, weight int) error {
+	if weight < 1 {
+		return fmt.Errorf("Weight must be positive")
+	}
+	s.ring.Append(&wrrEntry[T]{value, weight})
+	s.maxWeight = max(s.maxWeight, weight)
+	return nil
+}
+
+// Remove 删除所有满足 match 的成员，返回删除的个数。
+// 用调度器自己的游标绕环一圈完成删除，结束时游标回到原来的位置
+func (s *WeightedRoundRobin[T]) Remove(match func(T) bool) int {
+	removed := 0
+	s.maxWeight = 0
+	for n := s.ring.Len(); n > 0; n-- {
+		e, _ := s.cursor.Value()
+		if match(e.value) {
+			s.cursor.Remove()
+			removed++
+		} else {
+			s.maxWeight = max(s.maxWeight, e.weight)
+			s.cursor.Next()
+		}
+	}
+	if s.round > s.maxWeight {
+		s.round = 1
+	}
+	return removed
+}
+
+// Len 返回成员个数
+func (s *WeightedRoundRobin[T]) Len() int {
+	return s.ring.Len()
+}
+
+// Next 返回下一个被调度的成员，没有成员时返回 false
+func (s *WeightedRoundRobin[T]) Next() (T, bool) {
+	for {
+		e, ok := s.cursor.Value()
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		chosen := e.weight >= s.round
+		s.cursor.Next()
+		if s.cursor.AtHead() {
+			s.round++
+			if s.round > s.maxWeight {
+				s.round = 1
+			}
+		}
+		if chosen {
+			return e.value, true
+		}
+	}
+}
+
+// Josephus 约瑟夫问题：n 个人（编号 1..n）围成一圈，从 1 号开始报数，报到 k 的人出列，
+// 下一个人重新从 1 开始报数。返回出列顺序和最后剩下的人
+func Josephus(n, k int) ([]int, int) {
+	ring := CircularLinkedList[int]{}
+	for i := 1; i <= n; i++ {
+		ring.Append(i)
+	}
+	c := ring.Cursor()
+	var order []int
+	for ring.Len() > 1 {
+		for i := 1; i < k; i++ {
+			c.Next()
+		}
+		v, _ := c.Remove()
+		order = append(order, v)
+	}
+	survivor, _ := c.Value()
+	return order, survivor
+}
+
+// JosephusSurvivor 用递推公式 J(1) = 0，J(n) = (J(n-1) + k) mod n 计算幸存者编号，用于校验
+func JosephusSurvivor(n, k int) int {
+	j := 0
+	for i := 2; i <= n; i++ {
+		j = (j + k) % i
+	}
+	return j + 1
+}
@@ -0,0 +1,183 @@
+package main
+
+// 侵入式双向链表：链表指针不放在单独分配的节点里，而是由用户把 Link 嵌入到自己的结构体中。
+//
+//	type Job struct {
+//		Link[*Job]
+//		name string
+//	}
+//	jobs := NewList[*Job]()
+//
+// 元素本身就是节点，拿到元素即拿到了节点句柄，所以插入、删除、移动都是 O(1)，
+// 也不需要像 container/list 那样通过 Element.Value 做类型断言。
+// 每个 Link 记录自己所属的链表；检查模式下会用它发现重复插入和从错误的链表中删除，
+// 普通模式下这些检查被跳过，调用者需要自己保证正确使用。
+
+// Link 嵌入到元素结构体中的链表指针，E 是元素的指针类型
+type Link[E any] struct {
+	prev, next E
+	list       any // 所属的 *List[E]，不在任何链表中时为 nil
+}
+
+func (l *Link[E]) link() *Link[E] {
+	return l
+}
+
+// Linkable 嵌入了 Link[E] 的元素指针类型
+type Linkable[E any] interface {
+	comparable
+	link() *Link[E]
+}
+
+// List 侵入式双向链表，元素类型 E 必须嵌入 Link[E]
+type List[E Linkable[E]] struct {
+	head, tail E
+	length     int
+	checked    bool
+}
+
+// NewList 创建普通模式的链表
+func NewList[E Linkable[E]]() *List[E] {
+	return &List[E]{}
+}
+
+// NewCheckedList 创建检查模式的链表，误用时 panic
+func NewCheckedList[E Linkable[E]]() *List[E] {
+	return &List[E]{checked: true}
+}
+
+func (l *List[E]) Len() int {
+	return l.length
+}
+
+// Front 返回第一个元素，空链表返回零值（nil）
+func (l *List[E]) Front() E {
+	return l.head
+}
+
+// Back 返回最后一个元素，空链表返回零值（nil）
+func (l *List[E]) Back() E {
+	return l.tail
+}
+
+// Next 返回 e 的后一个元素
+func (l *List[E]) Next(e E) E {
+	return e.link().next
+}
+
+// Prev 返回 e 的前一个元素
+func (l *List[E]) Prev(e E) E {
+	return e.link().prev
+}
+
+// Contains 判断 e 是否在该链表中，O(1)
+func (l *List[E]) Contains(e E) bool {
+	return e.link().list == any(l)
+}
+
+func (l *List[E]) mustBeFree(e E) {
+	if l.checked && e.link().list != nil {
+		if e.link().list == any(l) {
+			panic("intrusive list: element inserted twice")
+		}
+		panic("intrusive list: element already belongs to another list")
+	}
+}
+
+func (l *List[E]) mustContain(e E) {
+	if l.checked && e.link().list != any(l) {
+		panic("intrusive list: element does not belong to this list")
+	}
+}
+
+// 把 e 插入到 prev 和 next 之间，prev 或 next 为零值表示链表头或尾
+func (l *List[E]) insertBetween(e, prev, next E) {
+	var zero E
+	link := e.link()
+	link.prev, link.next, link.list = prev, next, l
+	if prev == zero {
+		l.head = e
+	} else {
+		prev.link().next = e
+	}
+	if next == zero {
+		l.tail = e
+	} else {
+		next.link().prev = e
+	}
+	l.length++
+}
+
+// 把 e 从链表中摘下
+func (l *List[E]) unlink(e E) {
+	var zero E
+	link := e.link()
+	if link.prev == zero {
+		l.head = link.next
+	} else {
+		link.prev.link().next = link.next
+	}
+	if link.next == zero {
+		l.tail = link.prev
+	} else {
+		link.next.link().prev = link.prev
+	}
+	link.prev, link.next, link.list = zero, zero, nil
+	l.length--
+}
+
+// PushFront 在头部插入 e
+func (l *List[E]) PushFront(e E) {
+	var zero E
+	l.mustBeFree(e)
+	l.insertBetween(e, zero, l.head)
+}
+
+// PushBack 在尾部插入 e
+func (l *List[E]) PushBack(e E) {
+	var zero E
+	l.mustBeFree(e)
+	l.insertBetween(e, l.tail, zero)
+}
+
+// InsertBefore 把 e 插入到 mark 之前
+func (l *List[E]) InsertBefore(e, mark E) {
+	l.mustBeFree(e)
+	l.mustContain(mark)
+	l.insertBetween(e, mark.link().prev, mark)
+}
+
+// InsertAfter 把 e 插入到 mark 之后
+func (l *List[E]) InsertAfter(e, mark E) {
+	l.mustBeFree(e)
+	l.mustContain(mark)
+	l.insertBetween(e, mark, mark.link().next)
+}
+
+// Remove 删除 e
+func (l *List[E]) Remove(e E) {
+	l.mustContain(e)
+	l.unlink(e)
+}
+
+// MoveToFront 把 e 移动到头部
+func (l *List[E]) MoveToFront(e E) {
+	var zero E
+	l.mustContain(e)
+	if l.head == e {
+		return
+	}
+	l.unlink(e)
+	l.insertBetween(e, zero, l.head)
+}
+
+// MoveToBack 把 e 移动到尾部
+func (l *List[E]) MoveToBack(e E) {
+	var zero E
+	l.mustContain(e)
+	if l.tail == e {
+		return
+	}
+	l.unlink(e)
+	l.insertBetween(e, l.tail, zero)
+}
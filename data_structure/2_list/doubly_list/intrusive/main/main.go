@@ -0,0 +1,103 @@
+package main
+
+import "fmt"
+
+// 用侵入式链表实现的 LRU 缓存，与 lru 目录中基于 container/list 的版本相比，
+// 缓存项本身就是链表节点，不需要额外分配 Element，也不需要类型断言
+type cacheItem struct {
+	Link[*cacheItem]
+	key   int
+	value int
+}
+
+type LRUCache struct {
+	capacity int
+	cache    map[int]*cacheItem
+	list     *List[*cacheItem]
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		cache:    make(map[int]*cacheItem),
+		list:     NewList[*cacheItem](),
+	}
+}
+
+func (c *LRUCache) Get(key int) int {
+	if item, ok := c.cache[key]; ok {
+		c.list.MoveToFront(item)
+		return item.value
+	}
+	return -1
+}
+
+func (c *LRUCache) Put(key int, value int) {
+	if item, ok := c.cache[key]; ok {
+		item.value = value
+		c.list.MoveToFront(item)
+		return
+	}
+	item := &cacheItem{key: key, value: value}
+	c.list.PushFront(item)
+	c.cache[key] = item
+	if c.list.Len() > c.capacity {
+		last := c.list.Back()
+		c.list.Remove(last)
+		delete(c.cache, last.key)
+	}
+}
+
+// 调度器中的任务，同一时刻只会出现在就绪队列或阻塞队列之一
+type task struct {
+	Link[*task]
+	name string
+}
+
+func names(l *List[*task]) []string {
+	var result []string
+	for t := l.Front(); t != nil; t = l.Next(t) {
+		result = append(result, t.name)
+	}
+	return result
+}
+
+// 捕获检查模式下的 panic 并打印
+func try(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("panic:", r)
+		}
+	}()
+	fn()
+}
+
+func main() {
+	cache := NewLRUCache(2)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	fmt.Println(cache.Get(1)) // 1
+	cache.Put(3, 3)
+	fmt.Println(cache.Get(2)) // -1
+
+	ready := NewCheckedList[*task]()
+	blocked := NewCheckedList[*task]()
+	a, b, c := &task{name: "a"}, &task{name: "b"}, &task{name: "c"}
+	ready.PushBack(a)
+	ready.PushBack(c)
+	ready.InsertAfter(b, a)
+	fmt.Println(names(ready)) // [a b c]
+
+	// 时间片用完的任务移到队尾
+	ready.MoveToBack(a)
+	fmt.Println(names(ready)) // [b c a]
+
+	// 任务阻塞：O(1) 地从就绪队列移到阻塞队列
+	ready.Remove(c)
+	blocked.PushBack(c)
+	fmt.Println(names(ready), names(blocked)) // [b a] [c]
+
+	try(func() { ready.PushBack(a) }) // panic: intrusive list: element inserted twice
+	try(func() { ready.PushBack(c) }) // panic: intrusive list: element already belongs to another list
+	try(func() { blocked.Remove(a) }) // panic: intrusive list: element does not belong to this list
+}
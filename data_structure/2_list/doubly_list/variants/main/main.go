@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// 三种实现共同的接口，与 DoublyLinkedList 的方法一致
+type list interface {
+	Append(data interface{})
+	Prepend(data interface{})
+	Remove(index int) error
+	TraverseFromHead() []interface{}
+	TraverseFromTail() []interface{}
+}
+
+const benchN = 10000
+
+// 追加 n 个元素的耗时与内存
+func benchAppend(newList func() list) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l := newList()
+			for j := 0; j < benchN; j++ {
+				l.Append(j + 1000)
+			}
+		}
+	}
+}
+
+// 顺序遍历
+func benchTraverse(newList func() list) func(b *testing.B) {
+	return func(b *testing.B) {
+		l := newList()
+		for j := 0; j < benchN; j++ {
+			l.Append(j + 1000)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			l.TraverseFromHead()
+		}
+	}
+}
+
+// 反复删除中间元素
+func benchRemoveMiddle(newList func() list) func(b *testing.B) {
+	return func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			l := newList()
+			for j := 0; j < 2000; j++ {
+				l.Append(j + 1000)
+			}
+			b.StartTimer()
+			for n := 2000; n > 0; n-- {
+				l.Remove(n / 2)
+			}
+		}
+	}
+}
+
+func main() {
+	lists := []struct {
+		name string
+		new  func() list
+	}{
+		{"双向链表", func() list { return &DoublyLinkedList{} }},
+		{"展开链表", func() list { return &UnrolledLinkedList{} }},
+		{"异或链表", func() list { return NewXORLinkedList() }},
+	}
+
+	for _, impl := range lists {
+		l := impl.new()
+		l.Append(1)
+		l.Append(2)
+		l.Append(3)
+		l.Prepend(0)
+		fmt.Println(impl.name, l.TraverseFromHead(), l.TraverseFromTail())
+		l.Remove(2)
+		fmt.Println(impl.name, l.TraverseFromHead(), l.TraverseFromTail())
+	}
+
+	// 展开链表的分裂与合并
+	u := &UnrolledLinkedList{}
+	for i := 0; i < 40; i++ {
+		u.Append(i)
+	}
+	u.Insert(5, "x") // 第一个节点已满，分裂
+	for i := 0; i < 10; i++ {
+		u.Remove(20) // 节点不足半满时与后继合并或借元素
+	}
+	v, _ := u.Get(5)
+	fmt.Println(v, len(u.TraverseFromHead())) // x 31
+
+	// 反复在头部插入，除最后一个节点外仍然都至少半满
+	u = &UnrolledLinkedList{}
+	for i := 0; i < 1000; i++ {
+		u.Prepend(i)
+	}
+	halfFull := true
+	for n := u.head; n != u.tail; n = n.next {
+		halfFull = halfFull && len(n.items) >= nodeCapacity/2
+	}
+	first, _ := u.Get(0)
+	fmt.Println("头部插入 1000 个：", first, halfFull) // 999 true
+
+	x := NewXORLinkedList()
+	for i := 0; i < 5; i++ {
+		x.Append(i)
+	}
+	x.Insert(2, "y")
+	x.Remove(0)
+	fmt.Println(x.TraverseFromHead(), x.TraverseFromTail()) // [1 y 2 3 4] [4 3 2 y 1]
+
+	for _, bench := range []struct {
+		name string
+		fn   func(func() list) func(*testing.B)
+	}{
+		{"追加", benchAppend},
+		{"遍历", benchTraverse},
+		{"删除中间元素", benchRemoveMiddle},
+	} {
+		for _, impl := range lists {
+			r := testing.Benchmark(bench.fn(impl.new))
+			fmt.Printf("%s %s: %s %s\n", bench.name, impl.name, r, r.MemString())
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import "fmt"
+
+// 展开链表（unrolled linked list）：每个节点保存一个最多 nodeCapacity 个元素的小数组。
+// 相比每个元素一个节点的双向链表，指针开销被分摊到多个元素上，顺序遍历时也更容易命中 CPU 缓存。
+// 插入时节点满了就对半分裂；删除后节点不足半满，就与后继节点合并，合并不下则从后继借元素，
+// 从而保证除最后一个节点外每个节点至少半满。
+
+const nodeCapacity = 16
+
+type unrolledNode struct {
+	items []interface{}
+	prev  *unrolledNode
+	next  *unrolledNode
+}
+
+func newUnrolledNode() *unrolledNode {
+	return &unrolledNode{items: make([]interface{}, 0, nodeCapacity)}
+}
+
+type UnrolledLinkedList struct {
+	head   *unrolledNode
+	tail   *unrolledNode
+	length int
+}
+
+// 在 node 之后插入新节点，node 为 nil 时插入到头部
+func (list *UnrolledLinkedList) insertNodeAfter(node, n *unrolledNode) {
+	n.prev = node
+	if node == nil {
+		n.next = list.head
+		list.head = n
+	} else {
+		n.next = node.next
+		node.next = n
+	}
+	if n.next == nil {
+		list.tail = n
+	} else {
+		n.next.prev = n
+	}
+}
+
+func (list *UnrolledLinkedList) removeNode(n *unrolledNode) {
+	if n.prev == nil {
+		list.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		list.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+}
+
+// 找到下标 index 所在的节点及其在节点内的偏移，从离得较近的一端开始找
+func (list *UnrolledLinkedList) locate(index int) (*unrolledNode, int) {
+	if index < list.length/2 {
+		n := list.head
+		for index >= len(n.items) {
+			index -= len(n.items)
+			n = n.next
+		}
+		return n, index
+	}
+	n := list.tail
+	rest := list.length - index // index 之后（含）的元素个数
+	for rest > len(n.items) {
+		rest -= len(n.items)
+		n = n.prev
+	}
+	return n, len(n.items) - rest
+}
+
+func (list *UnrolledLinkedList) Append(data interface{}) {
+	if list.tail == nil || len(list.tail.items) == nodeCapacity {
+		list.insertNodeAfter(list.tail, newUnrolledNode())
+	}
+	list.tail.items = append(list.tail.items, data)
+	list.length++
+}
+
+func (list *UnrolledLinkedList) Prepend(data interface{}) {
+	if list.head == nil {
+		list.insertNodeAfter(nil, newUnrolledNode())
+	} else if len(list.head.items) == nodeCapacity {
+		// 与 Insert 一样对半分裂，不能在前面插入一个只有一个元素的新节点
+		list.split(list.head)
+	}
+	insertAt(&list.head.items, 0, data)
+	list.length++
+}
+
+// 把已满的节点 n 的后一半移到紧跟在它后面的新节点中，返回新节点
+func (list *UnrolledLinkedList) split(n *unrolledNode) *unrolledNode {
+	half := nodeCapacity / 2
+	m := newUnrolledNode()
+	m.items = append(m.items, n.items[half:]...)
+	clear(n.items[half:])
+	n.items = n.items[:half]
+	list.insertNodeAfter(n, m)
+	return m
+}
+
+// Insert 在下标 index 处插入元素
+func (list *UnrolledLinkedList) Insert(index int, data interface{}) error {
+	if index < 0 || index > list.length {
+		return fmt.Errorf("Index out of range")
+	}
+	if index == list.length {
+		list.Append(data)
+		return nil
+	}
+	n, offset := list.locate(index)
+	if len(n.items) == nodeCapacity {
+		// 节点已满，把后一半移到新节点
+		m := list.split(n)
+		if half := len(n.items); offset > half {
+			n, offset = m, offset-half
+		}
+	}
+	insertAt(&n.items, offset, data)
+	list.length++
+	return nil
+}
+
+// Get 返回下标 index 处的元素
+func (list *UnrolledLinkedList) Get(index int) (interface{}, error) {
+	if index < 0 || index >= list.length {
+		return nil, fmt.Errorf("Index out of range")
+	}
+	n, offset := list.locate(index)
+	return n.items[offset], nil
+}
+
+func (list *UnrolledLinkedList) Remove(index int) error {
+	if index < 0 || index >= list.length {
+		return fmt.Errorf("Index out of range")
+	}
+	n, offset := list.locate(index)
+	copy(n.items[offset:], n.items[offset+1:])
+	n.items[len(n.items)-1] = nil
+	n.items = n.items[:len(n.items)-1]
+	list.length--
+
+	if len(n.items) == 0 {
+		list.removeNode(n)
+		return nil
+	}
+	if len(n.items) >= nodeCapacity/2 || n.next == nil {
+		return nil
+	}
+	next := n.next
+	if len(n.items)+len(next.items) <= nodeCapacity {
+		// 合并后继节点
+		n.items = append(n.items, next.items...)
+		list.removeNode(next)
+	} else {
+		// 从后继节点借元素，使两个节点元素个数大致相同
+		move := (len(next.items) - len(n.items)) / 2
+		n.items = append(n.items, next.items[:move]...)
+		copy(next.items, next.items[move:])
+		clear(next.items[len(next.items)-move:])
+		next.items = next.items[:len(next.items)-move]
+	}
+	return nil
+}
+
+func (list *UnrolledLinkedList) TraverseFromHead() []interface{} {
+	var result []interface{}
+	for n := list.head; n != nil; n = n.next {
+		result = append(result, n.items...)
+	}
+	return result
+}
+
+func (list *UnrolledLinkedList) TraverseFromTail() []interface{} {
+	var result []interface{}
+	for n := list.tail; n != nil; n = n.prev {
+		for i := len(n.items) - 1; i >= 0; i-- {
+			result = append(result, n.items[i])
+		}
+	}
+	return result
+}
+
+// 在切片的 i 处插入元素，调用者保证容量足够
+func insertAt(items *[]interface{}, i int, data interface{}) {
+	s := append(*items, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = data
+	*items = s
+}
@@ -0,0 +1,141 @@
+package main
+
+import "fmt"
+
+// 异或链表（XOR linked list）：每个节点只保存一个 link = prev ^ next，
+// 从头部出发时已知 prev，就可以算出 next = link ^ prev，反过来从尾部出发也一样，
+// 因此用一个字段实现了双向遍历，节省一个指针的空间。
+// Go 的垃圾回收器不允许对指针做异或，这里把节点放在一个切片（arena）中，用下标代替指针，
+// 下标 0 保留作为空节点。删除的节点下标放入空闲列表，之后插入时复用。
+
+type xorNode struct {
+	data interface{}
+	link int // 前驱下标 ^ 后继下标
+}
+
+type XORLinkedList struct {
+	nodes  []xorNode // 节点 arena，nodes[0] 不使用
+	free   []int     // 可复用的节点下标
+	head   int
+	tail   int
+	length int
+}
+
+func NewXORLinkedList() *XORLinkedList {
+	return &XORLinkedList{nodes: make([]xorNode, 1)}
+}
+
+// 分配一个节点
+func (list *XORLinkedList) alloc(data interface{}, link int) int {
+	if n := len(list.free); n > 0 {
+		idx := list.free[n-1]
+		list.free = list.free[:n-1]
+		list.nodes[idx] = xorNode{data, link}
+		return idx
+	}
+	list.nodes = append(list.nodes, xorNode{data, link})
+	return len(list.nodes) - 1
+}
+
+// 在相邻的 prev 和 next 之间插入节点，0 表示链表头或尾之外
+func (list *XORLinkedList) insertBetween(prev, next int, data interface{}) {
+	idx := list.alloc(data, prev^next)
+	if prev == 0 {
+		list.head = idx
+	} else {
+		list.nodes[prev].link ^= next ^ idx
+	}
+	if next == 0 {
+		list.tail = idx
+	} else {
+		list.nodes[next].link ^= prev ^ idx
+	}
+	list.length++
+}
+
+// 返回下标 index 处的节点及其前驱、后继，从离得较近的一端开始找
+func (list *XORLinkedList) locate(index int) (prev, cur, next int) {
+	if index < list.length/2 {
+		prev, cur = 0, list.head
+		for i := 0; i < index; i++ {
+			prev, cur = cur, list.nodes[cur].link^prev
+		}
+		return prev, cur, list.nodes[cur].link ^ prev
+	}
+	next, cur = 0, list.tail
+	for i := list.length - 1; i > index; i-- {
+		next, cur = cur, list.nodes[cur].link^next
+	}
+	return list.nodes[cur].link ^ next, cur, next
+}
+
+func (list *XORLinkedList) Append(data interface{}) {
+	list.insertBetween(list.tail, 0, data)
+}
+
+func (list *XORLinkedList) Prepend(data interface{}) {
+	list.insertBetween(0, list.head, data)
+}
+
+// Insert 在下标 index 处插入元素
+func (list *XORLinkedList) Insert(index int, data interface{}) error {
+	if index < 0 || index > list.length {
+		return fmt.Errorf("Index out of range")
+	}
+	if index == list.length {
+		list.Append(data)
+		return nil
+	}
+	prev, cur, _ := list.locate(index)
+	list.insertBetween(prev, cur, data)
+	return nil
+}
+
+// Get 返回下标 index 处的元素
+func (list *XORLinkedList) Get(index int) (interface{}, error) {
+	if index < 0 || index >= list.length {
+		return nil, fmt.Errorf("Index out of range")
+	}
+	_, cur, _ := list.locate(index)
+	return list.nodes[cur].data, nil
+}
+
+func (list *XORLinkedList) Remove(index int) error {
+	if index < 0 || index >= list.length {
+		return fmt.Errorf("Index out of range")
+	}
+	prev, cur, next := list.locate(index)
+	if prev == 0 {
+		list.head = next
+	} else {
+		list.nodes[prev].link ^= cur ^ next
+	}
+	if next == 0 {
+		list.tail = prev
+	} else {
+		list.nodes[next].link ^= cur ^ prev
+	}
+	list.nodes[cur] = xorNode{}
+	list.free = append(list.free, cur)
+	list.length--
+	return nil
+}
+
+// 从 start 出发沿链表遍历，start 为头时正向，为尾时反向
+func (list *XORLinkedList) traverse(start int) []interface{} {
+	var result []interface{}
+	prev, cur := 0, start
+	for cur != 0 {
+		result = append(result, list.nodes[cur].data)
+		prev, cur = cur, list.nodes[cur].link^prev
+	}
+	return result
+}
+
+func (list *XORLinkedList) TraverseFromHead() []interface{} {
+	return list.traverse(list.head)
+}
+
+func (list *XORLinkedList) TraverseFromTail() []interface{} {
+	return list.traverse(list.tail)
+}
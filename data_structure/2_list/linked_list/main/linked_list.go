@@ -1,36 +1,41 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
-type Node struct {
-	data interface{}
-	next *Node
+type Node[T any] struct {
+	data T
+	next *Node[T]
 }
 
-type LinkedList struct {
-	head   *Node
+type LinkedList[T any] struct {
+	head   *Node[T]
+	tail   *Node[T] // 尾节点，使 Append 为 O(1)
 	length int
 }
 
-func (list *LinkedList) Append(data interface{}) {
-	node := &Node{data, nil}
+func (list *LinkedList[T]) Append(data T) {
+	node := &Node[T]{data, nil}
 	if list.head == nil {
 		list.head = node
 	} else {
-		cur := list.head
-		for cur.next != nil {
-			cur = cur.next
-		}
-		cur.next = node
+		list.tail.next = node
 	}
+	list.tail = node
 	list.length++
 }
 
-func (list *LinkedList) Insert(index int, data interface{}) error {
+func (list *LinkedList[T]) Insert(index int, data T) error {
 	if index < 0 || index > list.length {
 		return fmt.Errorf("Index out of range")
 	}
-	node := &Node{data, nil}
+	if index == list.length {
+		list.Append(data)
+		return nil
+	}
+	node := &Node[T]{data, nil}
 	if index == 0 {
 		node.next = list.head
 		list.head = node
@@ -46,27 +51,34 @@ func (list *LinkedList) Insert(index int, data interface{}) error {
 	return nil
 }
 
-func (list *LinkedList) Delete(index int) error {
+func (list *LinkedList[T]) Delete(index int) error {
 	if index < 0 || index >= list.length {
 		return fmt.Errorf("Index out of range")
 	}
 	if index == 0 {
 		list.head = list.head.next
+		if list.head == nil {
+			list.tail = nil
+		}
 	} else {
 		cur := list.head
 		for i := 0; i < index-1; i++ {
 			cur = cur.next
 		}
 		cur.next = cur.next.next
+		if cur.next == nil {
+			list.tail = cur
+		}
 	}
 	list.length--
 	return nil
 }
 
-func (list *LinkedList) Search(data interface{}) (int, error) {
+// Find 返回第一个满足 match 的元素下标
+func (list *LinkedList[T]) Find(match func(T) bool) (int, error) {
 	cur := list.head
 	for i := 0; cur != nil; i++ {
-		if cur.data == data {
+		if match(cur.data) {
 			return i, nil
 		}
 		cur = cur.next
@@ -74,35 +86,263 @@ func (list *LinkedList) Search(data interface{}) (int, error) {
 	return -1, fmt.Errorf("Data not found")
 }
 
-func (list *LinkedList) Print() {
+func (list *LinkedList[T]) Len() int {
+	return list.length
+}
+
+// Slice 按顺序返回所有元素
+func (list *LinkedList[T]) Slice() []T {
+	result := make([]T, 0, list.length)
+	for cur := list.head; cur != nil; cur = cur.next {
+		result = append(result, cur.data)
+	}
+	return result
+}
+
+// Reverse 原地反转链表
+func (list *LinkedList[T]) Reverse() {
+	var prev *Node[T]
 	cur := list.head
+	list.tail = cur
 	for cur != nil {
-		fmt.Printf("%v ", cur.data)
-		cur = cur.next
+		next := cur.next
+		cur.next = prev
+		prev = cur
+		cur = next
+	}
+	list.head = prev
+}
+
+// Middle 返回中间元素，长度为偶数时返回靠前的那个。
+// 快指针每次走两步，慢指针每次走一步，快指针到达末尾时慢指针正好在中间
+func (list *LinkedList[T]) Middle() (T, error) {
+	if list.head == nil {
+		var zero T
+		return zero, fmt.Errorf("List is empty")
+	}
+	return middleNode(list.head).data, nil
+}
+
+func middleNode[T any](head *Node[T]) *Node[T] {
+	slow, fast := head, head.next
+	for fast != nil && fast.next != nil {
+		slow = slow.next
+		fast = fast.next.next
+	}
+	return slow
+}
+
+// MergeSort 归并排序，稳定，O(n log n) 时间，只调整指针不分配新节点
+func (list *LinkedList[T]) MergeSort(less func(a, b T) bool) {
+	list.head = mergeSort(list.head, less)
+	list.tail = nil
+	for cur := list.head; cur != nil; cur = cur.next {
+		list.tail = cur
+	}
+}
+
+func mergeSort[T any](head *Node[T], less func(a, b T) bool) *Node[T] {
+	if head == nil || head.next == nil {
+		return head
+	}
+	mid := middleNode(head)
+	right := mid.next
+	mid.next = nil
+	return merge(mergeSort(head, less), mergeSort(right, less), less)
+}
+
+// 合并两个有序链表，相等时优先取左边的节点以保证稳定
+func merge[T any](a, b *Node[T], less func(a, b T) bool) *Node[T] {
+	dummy := &Node[T]{}
+	tail := dummy
+	for a != nil && b != nil {
+		if less(b.data, a.data) {
+			tail.next, b = b, b.next
+		} else {
+			tail.next, a = a, a.next
+		}
+		tail = tail.next
+	}
+	if a != nil {
+		tail.next = a
+	} else {
+		tail.next = b
+	}
+	return dummy.next
+}
+
+// RemoveIf 删除所有满足 match 的元素，返回删除的个数
+func (list *LinkedList[T]) RemoveIf(match func(T) bool) int {
+	dummy := &Node[T]{next: list.head}
+	prev := dummy
+	removed := 0
+	for prev.next != nil {
+		if match(prev.next.data) {
+			prev.next = prev.next.next
+			removed++
+		} else {
+			prev = prev.next
+		}
+	}
+	list.head = dummy.next
+	if list.head == nil {
+		list.tail = nil
+	} else {
+		list.tail = prev
+	}
+	list.length -= removed
+	return removed
+}
+
+// Splice 把 other 的所有节点移动到下标 index 处，移动后 other 为空
+func (list *LinkedList[T]) Splice(index int, other *LinkedList[T]) error {
+	if index < 0 || index > list.length {
+		return fmt.Errorf("Index out of range")
 	}
-	fmt.Println()
+	if other == list {
+		return fmt.Errorf("Cannot splice a list into itself")
+	}
+	if other.head == nil {
+		return nil
+	}
+	if index == 0 {
+		other.tail.next = list.head
+		list.head = other.head
+		if list.tail == nil {
+			list.tail = other.tail
+		}
+	} else {
+		cur := list.head
+		for i := 0; i < index-1; i++ {
+			cur = cur.next
+		}
+		other.tail.next = cur.next
+		cur.next = other.head
+		if cur == list.tail {
+			list.tail = other.tail
+		}
+	}
+	list.length += other.length
+	other.head, other.tail, other.length = nil, nil, 0
+	return nil
+}
+
+// DetectCycle 用 Floyd 判圈算法检测环，存在环时返回环入口节点的下标。
+// 快慢指针相遇后，让一个指针回到头部，两者再以相同速度前进，再次相遇的位置就是环的入口
+func (list *LinkedList[T]) DetectCycle() (int, bool) {
+	slow, fast := list.head, list.head
+	for fast != nil && fast.next != nil {
+		slow = slow.next
+		fast = fast.next.next
+		if slow == fast {
+			index := 0
+			for p := list.head; p != slow; p = p.next {
+				slow = slow.next
+				index++
+			}
+			return index, true
+		}
+	}
+	return -1, false
+}
+
+// String 以 [1 2 3] 的形式返回链表内容
+func (list *LinkedList[T]) String() string {
+	return fmt.Sprintf("%v", list)
+}
+
+// Format 实现 fmt.Formatter，动词和宽度等参数作用于每个元素，
+// 例如 %x 以十六进制打印每个元素；%+v 以 1 -> 2 -> 3 的形式打印
+func (list *LinkedList[T]) Format(f fmt.State, verb rune) {
+	sep := " "
+	if f.Flag('+') {
+		sep = " -> "
+	} else {
+		f.Write([]byte("["))
+	}
+	format := elemFormat(f, verb)
+	for cur := list.head; cur != nil; cur = cur.next {
+		if cur != list.head {
+			f.Write([]byte(sep))
+		}
+		fmt.Fprintf(f, format, cur.data)
+	}
+	if !f.Flag('+') {
+		f.Write([]byte("]"))
+	}
+}
+
+// 按原格式的标志、宽度和精度构造单个元素的格式串，'+' 用于选择链表的打印形式，不传给元素
+func elemFormat(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		fmt.Fprintf(&b, "%d", w)
+	}
+	if p, ok := f.Precision(); ok {
+		fmt.Fprintf(&b, ".%d", p)
+	}
+	b.WriteRune(verb)
+	return b.String()
 }
 
 func main() {
-	list := &LinkedList{}
+	list := &LinkedList[int]{}
 	list.Append(1)
 	list.Append(2)
 	list.Append(3)
 	list.Append(4)
-	list.Print() // 1 2 3 4
+	fmt.Println(list) // [1 2 3 4]
 
 	list.Insert(0, 0)
 	list.Insert(5, 5)
-	list.Print() // 0 1 2 3 4 5
+	fmt.Println(list) // [0 1 2 3 4 5]
 
 	list.Delete(0)
 	list.Delete(4)
-	list.Print() // 1 2 3 4
+	fmt.Println(list) // [1 2 3 4]
 
-	index, err := list.Search(3)
+	index, err := list.Find(func(v int) bool { return v == 3 })
 	if err != nil {
 		fmt.Println(err)
 	} else {
 		fmt.Println("Data found at index", index)
 	}
+
+	list.Reverse()
+	fmt.Printf("%+v\n", list) // 4 -> 3 -> 2 -> 1
+	mid, _ := list.Middle()
+	fmt.Println("middle:", mid) // 3
+
+	type pair struct {
+		key   int
+		label string
+	}
+	pairs := &LinkedList[pair]{}
+	for i, k := range []int{3, 1, 2, 1, 3} {
+		pairs.Append(pair{k, fmt.Sprint("#", i)})
+	}
+	pairs.MergeSort(func(a, b pair) bool { return a.key < b.key })
+	fmt.Println(pairs) // [{1 #1} {1 #3} {2 #2} {3 #0} {3 #4}]
+
+	removed := list.RemoveIf(func(v int) bool { return v%2 == 0 })
+	fmt.Println("removed:", removed, list) // removed: 2 [3 1]
+
+	other := &LinkedList[int]{}
+	other.Append(10)
+	other.Append(11)
+	list.Splice(1, other)
+	list.Append(12)
+	fmt.Printf("%03d\n", list) // [003 010 011 001 012]
+
+	_, ok := list.DetectCycle()
+	fmt.Println("cycle:", ok)            // cycle: false
+	list.tail.next = list.head.next.next // 人为制造一个从尾部指回下标 2 的环
+	entry, ok := list.DetectCycle()
+	fmt.Println("cycle:", ok, "entry:", entry) // cycle: true entry: 2
 }
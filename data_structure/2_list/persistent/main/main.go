@@ -0,0 +1,84 @@
+package main
+
+import "fmt"
+
+func main() {
+	// 持久化栈：每次修改都得到新版本，旧版本保持不变
+	s0 := NewPersistentStack[int]()
+	s1 := s0.Push(1)
+	s2 := s1.Push(2)
+	s3 := s2.Push(3)
+	top, s4, _ := s3.Pop()
+	s5 := s4.Push(4)
+	fmt.Println(s3.Slice(), top, s4.Slice(), s5.Slice()) // [3 2 1] 3 [2 1] [4 2 1]
+
+	// 银行家队列
+	q := NewPersistentQueue[string]()
+	q = q.Enqueue("a").Enqueue("b").Enqueue("c")
+	snapshot := q
+	v, q, _ := q.Dequeue()
+	q = q.Enqueue("d")
+	fmt.Println(v, q.Slice(), snapshot.Slice()) // a [b c d] [a b c]
+
+	// 持久化向量：模拟区块链重组时按区块高度保存账户余额的历史版本
+	balances := NewPersistentVector[int]()
+	for i := 0; i < 100; i++ {
+		balances = balances.Append(1000)
+	}
+	history := map[int]PersistentVector[int]{0: balances} // O(1) 快照
+	for height := 1; height <= 5; height++ {
+		account := height * 17 % balances.Len()
+		old, _ := balances.Get(account)
+		balances, _ = balances.Set(account, old-height*10)
+		history[height] = balances
+	}
+	// 高度 3 之后的区块被重组掉，回滚到高度 3 的版本
+	balances = history[3]
+	for _, account := range []int{17, 34, 51, 68, 85} {
+		b, _ := balances.Get(account)
+		fmt.Print(b, " ")
+	}
+	fmt.Println() // 990 980 970 1000 1000
+
+	// 大量操作下与切片的结果对比，覆盖树长高和降低的情况
+	vec := NewPersistentVector[int]()
+	var ref []int
+	versions := []PersistentVector[int]{vec}
+	for i := 0; i < 40000; i++ {
+		vec = vec.Append(i)
+		ref = append(ref, i)
+		if i%1000 == 0 {
+			versions = append(versions, vec)
+		}
+	}
+	for i := 0; i < len(ref); i += 7 {
+		vec, _ = vec.Set(i, -i)
+		ref[i] = -i
+	}
+	for len(ref) > 1000 {
+		vec, _ = vec.Pop()
+		ref = ref[:len(ref)-1]
+	}
+	ok := vec.Len() == len(ref)
+	for i, want := range ref {
+		if got, _ := vec.Get(i); got != want {
+			ok = false
+		}
+	}
+	old := versions[len(versions)-1]
+	last, _ := old.Get(old.Len() - 1)
+	fmt.Println("与切片一致：", ok, "旧版本未受影响：", old.Len(), last) // true 39001 39000
+
+	// 零值即为空向量
+	var zero PersistentVector[int]
+	for i := 0; i < 1000; i++ {
+		zero = zero.Append(i)
+	}
+	ok = zero.Len() == 1000
+	for i := 0; i < 1000; i++ {
+		if got, _ := zero.Get(i); got != i {
+			ok = false
+		}
+	}
+	fmt.Println("零值向量追加 1000 个元素：", ok) // true
+}
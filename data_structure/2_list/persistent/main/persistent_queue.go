@@ -0,0 +1,145 @@
+package main
+
+import "sync"
+
+// 持久化队列：Okasaki 的银行家队列（banker's queue），见《Purely Functional Data Structures》6.3 节。
+//
+// 队列由前端的惰性流 front 和后端的 cons 链表 rear 组成，入队压入 rear，出队从 front 取。
+// 始终保持 |rear| <= |front|，一旦违反，就把 front 替换为惰性的 front ++ reverse(rear)。
+// 拼接是增量的，每次只在取头部时计算一步；反转要等 front 被消耗到原来的末尾时才一次性执行。
+// 每个惰性单元的结果会被记住（memoization），同一个版本被反复使用时也不会重复计算，
+// 因此即便以持久化的方式使用（对旧版本继续操作），所有操作仍是均摊 O(1)。
+
+// 惰性流的一个单元，nil 表示空流
+type stream[T any] struct {
+	once  sync.Once
+	thunk func() *streamCell[T]
+	cell  *streamCell[T]
+}
+
+type streamCell[T any] struct {
+	head T
+	tail *stream[T]
+}
+
+func lazy[T any](thunk func() *streamCell[T]) *stream[T] {
+	return &stream[T]{thunk: thunk}
+}
+
+// 构造一个已经求值的流
+func evaluated[T any](c *streamCell[T]) *stream[T] {
+	s := &stream[T]{cell: c}
+	s.once.Do(func() {})
+	return s
+}
+
+// 求值并记住结果，空流返回 nil
+func (s *stream[T]) force() *streamCell[T] {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		s.cell = s.thunk()
+		s.thunk = nil
+	})
+	return s.cell
+}
+
+// 增量拼接：每次求值只产生一个元素
+func appendStream[T any](a, b *stream[T]) *stream[T] {
+	return lazy(func() *streamCell[T] {
+		c := a.force()
+		if c == nil {
+			return b.force()
+		}
+		return &streamCell[T]{c.head, appendStream(c.tail, b)}
+	})
+}
+
+// 整体反转：第一次求值时反转整个链表
+func reverseLazy[T any](r *cell[T]) *stream[T] {
+	return lazy(func() *streamCell[T] {
+		var s *stream[T]
+		for c := r; c != nil; c = c.next {
+			s = evaluated(&streamCell[T]{c.val, s})
+		}
+		return s.force()
+	})
+}
+
+// PersistentQueue 持久化队列，零值即为空队列，可以按值复制
+type PersistentQueue[T any] struct {
+	front *stream[T]
+	flen  int
+	rear  *cell[T]
+	rlen  int
+}
+
+// NewPersistentQueue 创建一个空队列
+func NewPersistentQueue[T any]() PersistentQueue[T] {
+	return PersistentQueue[T]{}
+}
+
+// 恢复 |rear| <= |front| 的不变式
+func (q PersistentQueue[T]) check() PersistentQueue[T] {
+	if q.rlen <= q.flen {
+		return q
+	}
+	return PersistentQueue[T]{
+		front: appendStream(q.front, reverseLazy(q.rear)),
+		flen:  q.flen + q.rlen,
+	}
+}
+
+// Enqueue 返回在队尾加入 v 之后的新队列
+func (q PersistentQueue[T]) Enqueue(v T) PersistentQueue[T] {
+	q.rear = &cell[T]{v, q.rear}
+	q.rlen++
+	return q.check()
+}
+
+// Dequeue 返回队头元素以及出队后的新队列，空队列返回 false
+func (q PersistentQueue[T]) Dequeue() (T, PersistentQueue[T], bool) {
+	c := q.front.force()
+	if c == nil {
+		var zero T
+		return zero, q, false
+	}
+	q.front = c.tail
+	q.flen--
+	return c.head, q.check(), true
+}
+
+// Peek 返回队头元素
+func (q PersistentQueue[T]) Peek() (T, bool) {
+	c := q.front.force()
+	if c == nil {
+		var zero T
+		return zero, false
+	}
+	return c.head, true
+}
+
+func (q PersistentQueue[T]) IsEmpty() bool {
+	return q.flen == 0
+}
+
+func (q PersistentQueue[T]) Size() int {
+	return q.flen + q.rlen
+}
+
+// Slice 从队头到队尾返回所有元素
+func (q PersistentQueue[T]) Slice() []T {
+	result := make([]T, 0, q.Size())
+	for c := q.front.force(); c != nil; c = c.tail.force() {
+		result = append(result, c.head)
+	}
+	back := make([]T, 0, q.rlen)
+	for c := q.rear; c != nil; c = c.next {
+		back = append(back, c.val)
+	}
+	for i := len(back) - 1; i >= 0; i-- {
+		result = append(result, back[i])
+	}
+	return result
+}
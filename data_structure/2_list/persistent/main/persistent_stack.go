@@ -0,0 +1,61 @@
+package main
+
+// 持久化（不可变）栈：单向的 cons 链表。
+// Push 只是新建一个指向旧栈顶的节点，Pop 只是返回指向下一个节点的新栈，
+// 旧版本完全不受影响，新旧版本共享除新节点以外的全部结构，因此保留任意多个历史版本都只需 O(1) 额外空间。
+
+type cell[T any] struct {
+	val  T
+	next *cell[T]
+}
+
+// PersistentStack 持久化栈，零值即为空栈，可以按值复制
+type PersistentStack[T any] struct {
+	head *cell[T]
+	size int
+}
+
+// NewPersistentStack 创建一个空栈
+func NewPersistentStack[T any]() PersistentStack[T] {
+	return PersistentStack[T]{}
+}
+
+// Push 返回压入 v 之后的新栈
+func (s PersistentStack[T]) Push(v T) PersistentStack[T] {
+	return PersistentStack[T]{&cell[T]{v, s.head}, s.size + 1}
+}
+
+// Pop 返回栈顶元素以及弹出后的新栈，空栈返回 false
+func (s PersistentStack[T]) Pop() (T, PersistentStack[T], bool) {
+	if s.head == nil {
+		var zero T
+		return zero, s, false
+	}
+	return s.head.val, PersistentStack[T]{s.head.next, s.size - 1}, true
+}
+
+// Peek 返回栈顶元素
+func (s PersistentStack[T]) Peek() (T, bool) {
+	if s.head == nil {
+		var zero T
+		return zero, false
+	}
+	return s.head.val, true
+}
+
+func (s PersistentStack[T]) IsEmpty() bool {
+	return s.head == nil
+}
+
+func (s PersistentStack[T]) Size() int {
+	return s.size
+}
+
+// Slice 从栈顶到栈底返回所有元素
+func (s PersistentStack[T]) Slice() []T {
+	result := make([]T, 0, s.size)
+	for c := s.head; c != nil; c = c.next {
+		result = append(result, c.val)
+	}
+	return result
+}
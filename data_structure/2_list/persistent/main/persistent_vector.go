@@ -0,0 +1,213 @@
+package main
+
+// 持久化向量：与 Clojure 的 PersistentVector 相同的位分区向量树（bit-partitioned vector trie）。
+//
+// 元素存放在一棵 32 叉树的叶子中，下标的二进制每 5 位决定一层的分支，树高 log32(n)，
+// 一百万个元素也只有 4 层，所以 Get/Set/Append/Pop 都是“实际上的 O(1)”。
+// 修改时只复制从根到目标叶子的一条路径（路径复制），其余节点新旧版本共享。
+// 最后不满 32 个的元素单独放在 tail 中，Append 大多数时候只复制这个小数组。
+// 向量本身是不可变的值，保存一个快照只需复制结构体，O(1)。
+
+const (
+	vbits  = 5
+	vwidth = 1 << vbits
+	vmask  = vwidth - 1
+)
+
+// 内部节点使用 children，叶子节点使用 values
+type vnode[T any] struct {
+	children []*vnode[T]
+	values   []T
+}
+
+// PersistentVector 持久化向量，零值即为空向量，可以按值复制
+type PersistentVector[T any] struct {
+	count int
+	shift uint      // 根节点所在层的位移，树只有一层内部节点时为 vbits
+	root  *vnode[T] // 不包含 tail 中元素的树
+	tail  []T       // 最后不满一个叶子的元素
+}
+
+// NewPersistentVector 创建一个空向量
+func NewPersistentVector[T any]() PersistentVector[T] {
+	return PersistentVector[T]{shift: vbits, root: &vnode[T]{}}
+}
+
+func (v PersistentVector[T]) Len() int {
+	return v.count
+}
+
+// tail 中第一个元素的下标
+func (v PersistentVector[T]) tailOffset() int {
+	if v.count < vwidth {
+		return 0
+	}
+	return ((v.count - 1) >> vbits) << vbits
+}
+
+// 返回下标 i 所在的叶子数组
+func (v PersistentVector[T]) leafFor(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+	n := v.root
+	for level := v.shift; level > 0; level -= vbits {
+		n = n.children[(i>>level)&vmask]
+	}
+	return n.values
+}
+
+// Get 返回下标 i 处的元素
+func (v PersistentVector[T]) Get(i int) (T, bool) {
+	if i < 0 || i >= v.count {
+		var zero T
+		return zero, false
+	}
+	return v.leafFor(i)[i&vmask], true
+}
+
+// Append 返回在末尾追加 x 之后的新向量
+func (v PersistentVector[T]) Append(x T) PersistentVector[T] {
+	if v.root == nil {
+		// 零值向量，补上空的根节点
+		v.root, v.shift = &vnode[T]{}, vbits
+	}
+	// tail 还有空位，只复制 tail
+	if v.count-v.tailOffset() < vwidth {
+		tail := make([]T, len(v.tail), len(v.tail)+1)
+		copy(tail, v.tail)
+		v.tail = append(tail, x)
+		v.count++
+		return v
+	}
+
+	// tail 已满，把它作为叶子挂到树上
+	leaf := &vnode[T]{values: v.tail}
+	if (v.count >> vbits) > (1 << v.shift) {
+		// 根节点已满，树长高一层
+		v.root = &vnode[T]{children: []*vnode[T]{v.root, newPath(v.shift, leaf)}}
+		v.shift += vbits
+	} else {
+		v.root = v.pushTail(v.shift, v.root, leaf)
+	}
+	v.tail = []T{x}
+	v.count++
+	return v
+}
+
+// 沿路径复制节点，把叶子挂到最右侧
+func (v PersistentVector[T]) pushTail(level uint, parent *vnode[T], leaf *vnode[T]) *vnode[T] {
+	subidx := ((v.count - 1) >> level) & vmask
+	ret := &vnode[T]{children: append([]*vnode[T](nil), parent.children...)}
+	var child *vnode[T]
+	if level == vbits {
+		child = leaf
+	} else if subidx < len(parent.children) {
+		child = v.pushTail(level-vbits, parent.children[subidx], leaf)
+	} else {
+		child = newPath(level-vbits, leaf)
+	}
+	if subidx < len(ret.children) {
+		ret.children[subidx] = child
+	} else {
+		ret.children = append(ret.children, child)
+	}
+	return ret
+}
+
+// 为叶子构造一条从 level 层到叶子的单链路径
+func newPath[T any](level uint, leaf *vnode[T]) *vnode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &vnode[T]{children: []*vnode[T]{newPath(level-vbits, leaf)}}
+}
+
+// Set 返回把下标 i 处的元素改为 x 之后的新向量，下标越界时返回 false
+func (v PersistentVector[T]) Set(i int, x T) (PersistentVector[T], bool) {
+	if i < 0 || i >= v.count {
+		return v, false
+	}
+	if i >= v.tailOffset() {
+		tail := append([]T(nil), v.tail...)
+		tail[i&vmask] = x
+		v.tail = tail
+		return v, true
+	}
+	v.root = assoc(v.shift, v.root, i, x)
+	return v, true
+}
+
+func assoc[T any](level uint, n *vnode[T], i int, x T) *vnode[T] {
+	if level == 0 {
+		values := append([]T(nil), n.values...)
+		values[i&vmask] = x
+		return &vnode[T]{values: values}
+	}
+	children := append([]*vnode[T](nil), n.children...)
+	subidx := (i >> level) & vmask
+	children[subidx] = assoc(level-vbits, children[subidx], i, x)
+	return &vnode[T]{children: children}
+}
+
+// Pop 返回删除最后一个元素之后的新向量，空向量返回 false
+func (v PersistentVector[T]) Pop() (PersistentVector[T], bool) {
+	switch {
+	case v.count == 0:
+		return v, false
+	case v.count == 1:
+		return NewPersistentVector[T](), true
+	case v.count-v.tailOffset() > 1:
+		// tail 中还有其它元素，tail 不会被原地修改，直接截短即可共享
+		v.tail = v.tail[:len(v.tail)-1]
+		v.count--
+		return v, true
+	}
+
+	// tail 只剩一个元素，把树中最后一个叶子取回来作为新的 tail
+	v.tail = v.leafFor(v.count - 2)
+	root := v.popTail(v.shift, v.root)
+	if root == nil {
+		root = &vnode[T]{}
+	}
+	if v.shift > vbits && len(root.children) == 1 {
+		// 根节点只剩一个子节点，树降低一层
+		root = root.children[0]
+		v.shift -= vbits
+	}
+	v.root = root
+	v.count--
+	return v, true
+}
+
+// 沿路径复制节点并移除最右侧的叶子，子树变空时返回 nil
+func (v PersistentVector[T]) popTail(level uint, n *vnode[T]) *vnode[T] {
+	subidx := ((v.count - 2) >> level) & vmask
+	if level > vbits {
+		child := v.popTail(level-vbits, n.children[subidx])
+		if child == nil && subidx == 0 {
+			return nil
+		}
+		ret := &vnode[T]{children: append([]*vnode[T](nil), n.children[:subidx+1]...)}
+		if child == nil {
+			ret.children = ret.children[:subidx]
+		} else {
+			ret.children[subidx] = child
+		}
+		return ret
+	}
+	if subidx == 0 {
+		return nil
+	}
+	return &vnode[T]{children: append([]*vnode[T](nil), n.children[:subidx]...)}
+}
+
+// Slice 按顺序返回所有元素
+func (v PersistentVector[T]) Slice() []T {
+	result := make([]T, 0, v.count)
+	for i := 0; i < v.count; i += vwidth {
+		leaf := v.leafFor(i)
+		result = append(result, leaf...)
+	}
+	return result
+}
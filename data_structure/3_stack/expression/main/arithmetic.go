@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Arithmetic 表达式求值所用的数值类型
+type Arithmetic[T any] interface {
+	Parse(literal string) (T, error)     // 解析数字字面量
+	Unary(op string, x T) (T, error)     // 一元运算：+ -
+	Binary(op string, x, y T) (T, error) // 二元运算：+ - * / % ^
+}
+
+var (
+	errOverflow       = errors.New("integer overflow")
+	errDivisionByZero = errors.New("division by zero")
+	errNegativeExp    = errors.New("negative exponent")
+	errNonIntegerExp  = errors.New("exponent must be an integer")
+	errNonIntegerMod  = errors.New("% requires integer operands")
+	errExpTooLarge    = errors.New("exponent too large")
+)
+
+// 有理数乘方结果的分子、分母最多的位数，防止 2^99999999 这样的输入耗尽时间和内存
+const maxRatBits = 1 << 20
+
+// Int64Arithmetic 64 位整数运算。所有运算都检查溢出，溢出时返回错误而不是回绕；
+// 除法与 Go 一样向零截断
+type Int64Arithmetic struct{}
+
+func (Int64Arithmetic) Parse(literal string) (int64, error) {
+	return strconv.ParseInt(literal, 10, 64)
+}
+
+func (Int64Arithmetic) Unary(op string, x int64) (int64, error) {
+	if op == "-" {
+		if x == math.MinInt64 {
+			return 0, errOverflow
+		}
+		return -x, nil
+	}
+	return x, nil
+}
+
+func (a Int64Arithmetic) Binary(op string, x, y int64) (int64, error) {
+	switch op {
+	case "+":
+		if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+			return 0, errOverflow
+		}
+		return x + y, nil
+	case "-":
+		if (y < 0 && x > math.MaxInt64+y) || (y > 0 && x < math.MinInt64+y) {
+			return 0, errOverflow
+		}
+		return x - y, nil
+	case "*":
+		return mulInt64(x, y)
+	case "/", "%":
+		if y == 0 {
+			return 0, errDivisionByZero
+		}
+		if x == math.MinInt64 && y == -1 {
+			if op == "%" {
+				return 0, nil
+			}
+			return 0, errOverflow
+		}
+		if op == "/" {
+			return x / y, nil
+		}
+		return x % y, nil
+	default: // ^
+		if y < 0 {
+			return 0, errNegativeExp
+		}
+		// 快速幂
+		result, base := int64(1), x
+		for y > 0 {
+			var err error
+			if y&1 == 1 {
+				if result, err = mulInt64(result, base); err != nil {
+					return 0, err
+				}
+			}
+			y >>= 1
+			if y > 0 {
+				if base, err = mulInt64(base, base); err != nil {
+					return 0, err
+				}
+			}
+		}
+		return result, nil
+	}
+}
+
+func mulInt64(x, y int64) (int64, error) {
+	if x == 0 || y == 0 {
+		return 0, nil
+	}
+	c := x * y
+	if c/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64) {
+		return 0, errOverflow
+	}
+	return c, nil
+}
+
+// Float64Arithmetic 浮点运算，除以零时返回错误而不是 Inf
+type Float64Arithmetic struct{}
+
+func (Float64Arithmetic) Parse(literal string) (float64, error) {
+	return strconv.ParseFloat(literal, 64)
+}
+
+func (Float64Arithmetic) Unary(op string, x float64) (float64, error) {
+	if op == "-" {
+		return -x, nil
+	}
+	return x, nil
+}
+
+func (Float64Arithmetic) Binary(op string, x, y float64) (float64, error) {
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return 0, errDivisionByZero
+		}
+		return x / y, nil
+	case "%":
+		if y == 0 {
+			return 0, errDivisionByZero
+		}
+		return math.Mod(x, y), nil
+	default: // ^
+		return math.Pow(x, y), nil
+	}
+}
+
+// RatArithmetic 任意精度有理数运算，结果精确无舍入。
+// 每次运算都返回新的 big.Rat，不会修改操作数
+type RatArithmetic struct{}
+
+func (RatArithmetic) Parse(literal string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(literal)
+	if !ok {
+		return nil, errors.New("invalid number " + strconv.Quote(literal))
+	}
+	return r, nil
+}
+
+func (RatArithmetic) Unary(op string, x *big.Rat) (*big.Rat, error) {
+	if op == "-" {
+		return new(big.Rat).Neg(x), nil
+	}
+	return new(big.Rat).Set(x), nil
+}
+
+func (RatArithmetic) Binary(op string, x, y *big.Rat) (*big.Rat, error) {
+	switch op {
+	case "+":
+		return new(big.Rat).Add(x, y), nil
+	case "-":
+		return new(big.Rat).Sub(x, y), nil
+	case "*":
+		return new(big.Rat).Mul(x, y), nil
+	case "/":
+		if y.Sign() == 0 {
+			return nil, errDivisionByZero
+		}
+		return new(big.Rat).Quo(x, y), nil
+	case "%":
+		if !x.IsInt() || !y.IsInt() {
+			return nil, errNonIntegerMod
+		}
+		if y.Sign() == 0 {
+			return nil, errDivisionByZero
+		}
+		// 与 int64 一样，余数的符号与被除数相同
+		return new(big.Rat).SetInt(new(big.Int).Rem(x.Num(), y.Num())), nil
+	default: // ^
+		if !y.IsInt() {
+			return nil, errNonIntegerExp
+		}
+		exp := new(big.Int).Abs(y.Num())
+		// 底数为 0、1、-1 时结果不会变长
+		if bits := max(x.Num().BitLen(), x.Denom().BitLen()); bits > 1 &&
+			(!exp.IsInt64() || exp.Int64() > maxRatBits/int64(bits)) {
+			return nil, errExpTooLarge
+		}
+		num := new(big.Int).Exp(x.Num(), exp, nil)
+		den := new(big.Int).Exp(x.Denom(), exp, nil)
+		if y.Sign() < 0 {
+			if num.Sign() == 0 {
+				return nil, errDivisionByZero
+			}
+			num, den = den, num
+		}
+		return new(big.Rat).SetFrac(num, den), nil
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import "fmt"
+
+// 逆波兰表达式求值：从左到右扫描，操作数入栈，遇到运算符或函数时弹出所需个数的操作数，计算后把结果压回栈中。
+// 具体的数值类型由 Arithmetic 决定，同一个 Expression 可以分别按 int64、float64 或 big.Rat 求值。
+
+// Func 用户注册的函数
+type Func[T any] struct {
+	Arity int // 参数个数，-1 表示接受任意个（至少一个）参数
+	Fn    func(args []T) (T, error)
+}
+
+// Env 求值环境，保存变量和函数
+type Env[T any] struct {
+	vars  map[string]T
+	funcs map[string]Func[T]
+}
+
+// NewEnv 创建一个空的求值环境
+func NewEnv[T any]() *Env[T] {
+	return &Env[T]{
+		vars:  make(map[string]T),
+		funcs: make(map[string]Func[T]),
+	}
+}
+
+// Set 设置变量的值
+func (env *Env[T]) Set(name string, v T) {
+	env.vars[name] = v
+}
+
+// Register 注册函数，arity 为 -1 时表示可变参数
+func (env *Env[T]) Register(name string, arity int, fn func(args []T) (T, error)) {
+	env.funcs[name] = Func[T]{Arity: arity, Fn: fn}
+}
+
+// Evaluate 在环境 env 中按数值类型 arith 计算表达式的值
+func Evaluate[T any](e *Expression, arith Arithmetic[T], env *Env[T]) (T, error) {
+	var zero T
+	s := NewStack[T]()
+	for _, t := range e.rpn {
+		switch t.kind {
+		case tokNumber:
+			v, err := arith.Parse(t.text)
+			if err != nil {
+				return zero, fmt.Errorf("position %d: %w", t.pos, err)
+			}
+			s.Push(v)
+		case tokIdent:
+			v, ok := env.vars[t.text]
+			if !ok {
+				return zero, fmt.Errorf("position %d: undefined variable %q", t.pos, t.text)
+			}
+			s.Push(v)
+		case tokOp:
+			var v T
+			var err error
+			if t.unary {
+				x, _ := s.Pop()
+				v, err = arith.Unary(t.text, x)
+			} else {
+				y, _ := s.Pop()
+				x, _ := s.Pop()
+				v, err = arith.Binary(t.text, x, y)
+			}
+			if err != nil {
+				return zero, fmt.Errorf("position %d: %w", t.pos, err)
+			}
+			s.Push(v)
+		case tokFunc:
+			f, ok := env.funcs[t.text]
+			if !ok {
+				return zero, fmt.Errorf("position %d: undefined function %q", t.pos, t.text)
+			}
+			if (f.Arity >= 0 && t.argc != f.Arity) || (f.Arity < 0 && t.argc == 0) {
+				return zero, fmt.Errorf("position %d: %s called with %d arguments", t.pos, t.text, t.argc)
+			}
+			// PopN 返回的第一个元素是栈顶，即最后一个参数
+			args, _ := s.PopN(t.argc)
+			for i, j := 0, len(args)-1; i < j; i, j = i+1, j-1 {
+				args[i], args[j] = args[j], args[i]
+			}
+			v, err := f.Fn(args)
+			if err != nil {
+				return zero, fmt.Errorf("position %d: %s: %w", t.pos, t.text, err)
+			}
+			s.Push(v)
+		}
+	}
+	// Parse 已经保证了栈中恰好剩下一个结果
+	return s.Pop()
+}
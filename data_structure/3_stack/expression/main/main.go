@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+func main() {
+	for _, src := range []string{
+		"1 + 2 * 3",
+		"(1 + 2) * 3",
+		"2 ^ 3 ^ 2",
+		"-2 ^ 2",
+		"10 - 4 - 3",
+		"max(1, 2 * 3, 4) - min(5, -amount)",
+	} {
+		e, err := Parse(src)
+		if err != nil {
+			fmt.Println(src, "=>", err)
+			continue
+		}
+		fmt.Printf("%-36s RPN: %s\n", src, e.RPN())
+	}
+
+	// 手续费公式：按万分比收费，不低于最低手续费，不高于封顶
+	fee, _ := Parse("clamp(amount * rate_bps / 10000, min_fee, max_fee)")
+
+	ints := NewEnv[int64]()
+	ints.Set("rate_bps", 30)
+	ints.Set("min_fee", 100)
+	ints.Set("max_fee", 50000)
+	ints.Register("clamp", 3, func(args []int64) (int64, error) {
+		return max(args[1], min(args[0], args[2])), nil
+	})
+	for _, amount := range []int64{1000, 1000000, 100000000} {
+		ints.Set("amount", amount)
+		v, err := Evaluate(fee, Int64Arithmetic{}, ints)
+		fmt.Println("int64 手续费：", amount, "=>", v, err)
+	}
+
+	// 溢出时返回错误而不是回绕
+	big1, _ := Parse("9223372036854775807 + 1")
+	_, err := Evaluate(big1, Int64Arithmetic{}, ints)
+	fmt.Println("int64 溢出：", err)
+
+	// 同一个表达式按有理数精确求值
+	rats := NewEnv[*big.Rat]()
+	rats.Set("amount", big.NewRat(1, 3))
+	rats.Set("rate_bps", big.NewRat(30, 1))
+	rats.Set("min_fee", big.NewRat(0, 1))
+	rats.Set("max_fee", big.NewRat(1, 1))
+	rats.Register("clamp", 3, func(args []*big.Rat) (*big.Rat, error) {
+		v := args[0]
+		if v.Cmp(args[1]) < 0 {
+			v = args[1]
+		}
+		if v.Cmp(args[2]) > 0 {
+			v = args[2]
+		}
+		return v, nil
+	})
+	r, err := Evaluate(fee, RatArithmetic{}, rats)
+	fmt.Println("big.Rat 手续费：", r, err) // 1/1000
+
+	sum, _ := Parse("0.1 + 0.2")
+	r, _ = Evaluate(sum, RatArithmetic{}, rats)
+	f, _ := Evaluate(sum, Float64Arithmetic{}, NewEnv[float64]())
+	fmt.Println("0.1 + 0.2：big.Rat =", r.FloatString(20), "float64 =", f)
+
+	floats := NewEnv[float64]()
+	floats.Register("avg", -1, func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, errors.New("no arguments")
+		}
+		s := 0.0
+		for _, a := range args {
+			s += a
+		}
+		return s / float64(len(args)), nil
+	})
+	avg, _ := Parse("avg(1, 2, 3, 4) * 2 ^ -1")
+	f, err = Evaluate(avg, Float64Arithmetic{}, floats)
+	fmt.Println("float64：", f, err) // 1.25
+
+	// 语法错误
+	for _, src := range []string{"1 +", "(1 + 2", "1 + 2)", "2 3", "f(,1)", "f((1, 2))", "1 $ 2",
+		"1 + 2 3 *", "1 2 + 3 -", "(1)(2) +", "f(1 2 +)", "1 * / 2"} {
+		_, err := Parse(src)
+		fmt.Printf("%-10s => %v\n", src, err)
+	}
+
+	// 指数过大时拒绝计算，而不是耗尽时间和内存
+	huge, _ := Parse("2 ^ 99999999")
+	_, err = Evaluate(huge, RatArithmetic{}, rats)
+	fmt.Println("big.Rat 乘方：", err)
+}
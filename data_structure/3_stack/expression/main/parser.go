@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 调度场算法（shunting-yard）：借助一个运算符栈把中缀表达式转换成逆波兰表达式（RPN）。
+// 操作数直接输出；运算符入栈前，先把栈顶优先级更高（或相同且左结合）的运算符弹出到输出；
+// 左括号入栈，遇到右括号时弹出运算符直到左括号；函数名像运算符一样入栈，在对应的右括号处输出。
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota // 数字字面量
+	tokIdent                   // 变量
+	tokFunc                    // 函数名
+	tokOp                      // 运算符
+	tokLParen                  // 左括号
+	tokRParen                  // 右括号
+	tokComma                   // 函数参数分隔符
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	pos   int  // 在源字符串中的位置，用于报错
+	unary bool // 运算符是否为一元运算符
+	argc  int  // 函数调用的参数个数
+}
+
+func (t token) String() string {
+	switch {
+	case t.kind == tokOp && t.unary:
+		return "u" + t.text
+	case t.kind == tokFunc:
+		return fmt.Sprintf("%s/%d", t.text, t.argc)
+	default:
+		return t.text
+	}
+}
+
+type operator struct {
+	prec       int  // 优先级，数值越大越先计算
+	rightAssoc bool // 是否右结合
+}
+
+// 二元运算符，^ 为乘方，右结合：2^3^2 = 2^(3^2)
+var binaryOps = map[string]operator{
+	"+": {1, false},
+	"-": {1, false},
+	"*": {2, false},
+	"/": {2, false},
+	"%": {2, false},
+	"^": {4, true},
+}
+
+// 一元正负号的优先级低于乘方：-2^2 = -(2^2)
+var unaryOp = operator{3, true}
+
+// 取运算符的优先级与结合性
+func opInfo(t token) operator {
+	if t.unary {
+		return unaryOp
+	}
+	return binaryOps[t.text]
+}
+
+// Expression 解析后的表达式
+type Expression struct {
+	source string
+	rpn    []token
+}
+
+// String 返回原始的中缀表达式
+func (e *Expression) String() string {
+	return e.source
+}
+
+// RPN 返回逆波兰表达式，一元运算符以 u 为前缀，函数以 名称/参数个数 表示
+func (e *Expression) RPN() string {
+	parts := make([]string, len(e.rpn))
+	for i, t := range e.rpn {
+		parts[i] = t.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// 词法分析
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			kind := tokIdent
+			// 紧跟左括号的标识符是函数调用
+			j := i
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+			if j < len(runes) && runes[j] == '(' {
+				kind = tokFunc
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[start:i]), pos: start})
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		default:
+			if _, ok := binaryOps[string(r)]; !ok {
+				return nil, fmt.Errorf("position %d: unexpected character %q", i, r)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: i})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// Parse 把中缀表达式解析为逆波兰表达式
+func Parse(src string) (*Expression, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []token
+	ops := NewStack[token]() // 运算符栈
+	argc := NewStack[int]()  // 每层括号中已出现的参数个数
+	var prev *token          // 上一个词法单元，用于判断空参数列表
+	// 下一个词法单元应当是操作数（数字、变量、函数、左括号或一元运算符）还是二元运算符，
+	// 用来拒绝 "1 2 +"、"(1)(2)" 这样相邻的操作数和 "1 * / 2" 这样相邻的二元运算符
+	expectOperand := true
+
+	for i := range tokens {
+		t := tokens[i]
+		switch t.kind {
+		case tokNumber, tokIdent, tokFunc, tokLParen:
+			if !expectOperand {
+				return nil, fmt.Errorf("position %d: missing operator before %q", t.pos, t.text)
+			}
+		case tokOp:
+			if expectOperand && t.text != "+" && t.text != "-" {
+				return nil, fmt.Errorf("position %d: missing operand before %q", t.pos, t.text)
+			}
+		case tokComma, tokRParen:
+			// f() 的右括号前没有操作数
+			if expectOperand && !(t.kind == tokRParen && prev != nil && prev.kind == tokLParen) {
+				return nil, fmt.Errorf("position %d: missing operand before %q", t.pos, t.text)
+			}
+		}
+
+		switch t.kind {
+		case tokNumber, tokIdent:
+			output = append(output, t)
+			expectOperand = false
+		case tokFunc:
+			ops.Push(t)
+		case tokComma:
+			if err := popUntilLParen(ops, &output); err != nil {
+				return nil, fmt.Errorf("position %d: comma outside function call", t.pos)
+			}
+			if n, _ := argc.Peek(); n == 0 {
+				return nil, fmt.Errorf("position %d: comma outside function call", t.pos)
+			}
+			n, _ := argc.Pop()
+			argc.Push(n + 1)
+			expectOperand = true
+		case tokOp:
+			// 出现在需要操作数的位置（开头、运算符、左括号或逗号之后）的 + - 是一元运算符
+			if expectOperand {
+				t.unary = true
+				ops.Push(t)
+				break
+			}
+			cur := opInfo(t)
+			for !ops.IsEmpty() {
+				top, _ := ops.Peek()
+				if top.kind != tokOp {
+					break
+				}
+				info := opInfo(top)
+				if info.prec < cur.prec || (info.prec == cur.prec && cur.rightAssoc) {
+					break
+				}
+				ops.Pop()
+				output = append(output, top)
+			}
+			ops.Push(t)
+			expectOperand = true
+		case tokLParen:
+			// 每个左括号开启一层参数计数，普通括号记为 0，表示其中不允许出现逗号
+			if prev != nil && prev.kind == tokFunc {
+				argc.Push(1)
+			} else {
+				argc.Push(0)
+			}
+			ops.Push(t)
+		case tokRParen:
+			if err := popUntilLParen(ops, &output); err != nil {
+				return nil, fmt.Errorf("position %d: unmatched ')'", t.pos)
+			}
+			ops.Pop() // 丢弃左括号
+			n, _ := argc.Pop()
+			if top, err := ops.Peek(); err == nil && top.kind == tokFunc {
+				ops.Pop()
+				if prev.kind == tokLParen {
+					n = 0 // f() 没有参数
+				}
+				top.argc = n
+				output = append(output, top)
+			} else if prev.kind == tokLParen {
+				return nil, fmt.Errorf("position %d: empty parentheses", t.pos)
+			}
+			expectOperand = false
+		}
+		prev = &tokens[i]
+	}
+	if expectOperand {
+		return nil, fmt.Errorf("position %d: missing operand at end of expression", len([]rune(src)))
+	}
+
+	for !ops.IsEmpty() {
+		top, _ := ops.Pop()
+		if top.kind == tokLParen || top.kind == tokFunc {
+			return nil, fmt.Errorf("position %d: unmatched '('", top.pos)
+		}
+		output = append(output, top)
+	}
+
+	if err := validate(output); err != nil {
+		return nil, err
+	}
+	return &Expression{source: src, rpn: output}, nil
+}
+
+// 把运算符弹出到输出，直到栈顶为左括号；找不到左括号时返回错误
+func popUntilLParen(ops *Stack[token], output *[]token) error {
+	for {
+		top, err := ops.Peek()
+		if err != nil {
+			return err
+		}
+		if top.kind == tokLParen {
+			return nil
+		}
+		ops.Pop()
+		*output = append(*output, top)
+	}
+}
+
+// 模拟求值时的栈深度，再检查一遍生成的逆波兰表达式中操作数与运算符的个数是否匹配
+func validate(rpn []token) error {
+	depth := 0
+	for _, t := range rpn {
+		need := 0
+		switch t.kind {
+		case tokOp:
+			need = 2
+			if t.unary {
+				need = 1
+			}
+		case tokFunc:
+			need = t.argc
+		}
+		if depth < need {
+			return fmt.Errorf("position %d: missing operand for %q", t.pos, t.text)
+		}
+		depth = depth - need + 1
+	}
+	if depth != 1 {
+		return fmt.Errorf("malformed expression")
+	}
+	return nil
+}
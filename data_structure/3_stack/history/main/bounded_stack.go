@@ -0,0 +1,69 @@
+package main
+
+// 有深度上限的栈，用作撤销栈：元素存放在环形缓冲区中，栈满时再压入会直接覆盖栈底，
+// 丢弃最早的元素是 O(1)，不需要像普通栈那样全部弹出再压回
+
+type boundedStack[T any] struct {
+	buf   []T // 环形缓冲区，栈底在 head
+	head  int
+	size  int
+	limit int // 最多保留的元素个数，<= 0 表示不限
+}
+
+func newBoundedStack[T any](limit int) *boundedStack[T] {
+	return &boundedStack[T]{limit: limit}
+}
+
+// Push 压入元素，栈已满时丢弃栈底元素并返回它
+func (s *boundedStack[T]) Push(val T) (dropped T, ok bool) {
+	if s.limit > 0 && s.size == s.limit {
+		// 缓冲区长度恰好为 limit，栈顶的下一个位置就是栈底
+		dropped, ok = s.buf[s.head], true
+		s.buf[s.head] = val
+		s.head = (s.head + 1) % len(s.buf)
+		return dropped, ok
+	}
+	if s.size == len(s.buf) {
+		s.grow()
+	}
+	s.buf[(s.head+s.size)%len(s.buf)] = val
+	s.size++
+	return dropped, false
+}
+
+// 缓冲区容量翻倍（不超过 limit），并把元素重新排列到开头
+func (s *boundedStack[T]) grow() {
+	n := max(minCapacity, 2*len(s.buf))
+	if s.limit > 0 {
+		n = min(n, s.limit)
+	}
+	buf := make([]T, n)
+	for i := 0; i < s.size; i++ {
+		buf[i] = s.buf[(s.head+i)%len(s.buf)]
+	}
+	s.buf, s.head = buf, 0
+}
+
+func (s *boundedStack[T]) Pop() (T, error) {
+	var zero T
+	if s.size == 0 {
+		return zero, ErrEmpty
+	}
+	i := (s.head + s.size - 1) % len(s.buf)
+	val := s.buf[i]
+	s.buf[i] = zero // 清除引用，避免阻止 GC 回收
+	s.size--
+	return val, nil
+}
+
+func (s *boundedStack[T]) Peek() (T, error) {
+	if s.size == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return s.buf[(s.head+s.size-1)%len(s.buf)], nil
+}
+
+func (s *boundedStack[T]) IsEmpty() bool {
+	return s.size == 0
+}
@@ -0,0 +1,266 @@
+package main
+
+import "errors"
+
+// 基于两个栈的撤销/重做历史。
+// 执行命令后把它压入撤销栈，并清空重做栈；撤销时从撤销栈弹出、执行逆操作后压入重做栈；重做则反过来。
+// 多个命令可以组成一个事务，事务整体作为撤销栈中的一项，一次撤销或重做。
+// 检查点给某个历史位置起名字，之后可以直接跳回该位置。
+
+var (
+	ErrNothingToUndo     = errors.New("nothing to undo")
+	ErrNothingToRedo     = errors.New("nothing to redo")
+	ErrInTransaction     = errors.New("transaction in progress")
+	ErrNoTransaction     = errors.New("no transaction in progress")
+	ErrUnknownCheckpoint = errors.New("unknown checkpoint")
+	ErrCheckpointTrimmed = errors.New("checkpoint is beyond history depth")
+)
+
+// Command 可逆的命令
+type Command[S any] interface {
+	Do(state S) (S, error)
+	Undo(state S) (S, error)
+}
+
+type funcCommand[S any] struct {
+	do, undo func(S) (S, error)
+}
+
+func (c funcCommand[S]) Do(state S) (S, error)   { return c.do(state) }
+func (c funcCommand[S]) Undo(state S) (S, error) { return c.undo(state) }
+
+// NewCommand 用一对函数构造命令
+func NewCommand[S any](do, undo func(S) (S, error)) Command[S] {
+	return funcCommand[S]{do, undo}
+}
+
+// 撤销栈中的一项：单个命令，或者一个事务中的所有命令
+type entry[S any] struct {
+	seq   int // 序号，按执行顺序递增
+	label string
+	cmds  []Command[S]
+}
+
+// 正在进行的事务
+type group[S any] struct {
+	label string
+	cmds  []Command[S]
+}
+
+// History 状态 S 上的命令历史
+type History[S any] struct {
+	state       S
+	maxDepth    int                      // 撤销栈最多保留的项数，<= 0 表示不限
+	undo        *boundedStack[*entry[S]] // 已执行的命令，最多 maxDepth 项
+	redo        *Stack[*entry[S]]        // 已撤销、可以重做的命令
+	txs         *Stack[*group[S]]        // 嵌套的事务
+	seq         int                      // 最近分配的序号
+	trimmed     int                      // 因超出深度被丢弃的最大序号
+	checkpoints map[string]int           // 检查点名称 -> 该位置的序号
+}
+
+// NewHistory 创建一个初始状态为 initial、最多可撤销 maxDepth 步的历史
+func NewHistory[S any](initial S, maxDepth int) *History[S] {
+	return &History[S]{
+		state:       initial,
+		maxDepth:    maxDepth,
+		undo:        newBoundedStack[*entry[S]](maxDepth),
+		redo:        NewStack[*entry[S]](),
+		txs:         NewStack[*group[S]](),
+		checkpoints: make(map[string]int),
+	}
+}
+
+// State 返回当前状态
+func (h *History[S]) State() S {
+	return h.state
+}
+
+// CanUndo 是否可以撤销
+func (h *History[S]) CanUndo() bool {
+	return !h.undo.IsEmpty() && h.txs.IsEmpty()
+}
+
+// CanRedo 是否可以重做
+func (h *History[S]) CanRedo() bool {
+	return !h.redo.IsEmpty() && h.txs.IsEmpty()
+}
+
+// Do 执行命令并记录到历史中，命令执行失败时状态和历史都不变。
+// 事务进行中时命令并入事务，label 被忽略，撤销栈中只记录最外层 Begin 的标签
+func (h *History[S]) Do(label string, cmd Command[S]) error {
+	state, err := cmd.Do(h.state)
+	if err != nil {
+		return err
+	}
+	h.state = state
+	if tx, err := h.txs.Peek(); err == nil {
+		tx.cmds = append(tx.cmds, cmd)
+		return nil
+	}
+	h.record(label, []Command[S]{cmd})
+	return nil
+}
+
+// 把已执行的命令压入撤销栈，丢弃重做栈及指向被丢弃分支的检查点
+func (h *History[S]) record(label string, cmds []Command[S]) {
+	for name, seq := range h.checkpoints {
+		if seq > h.top() {
+			delete(h.checkpoints, name)
+		}
+	}
+	h.seq++
+	// 撤销栈超出深度时丢弃最早的项
+	if dropped, ok := h.undo.Push(&entry[S]{seq: h.seq, label: label, cmds: cmds}); ok {
+		h.trimmed = dropped.seq
+	}
+	h.redo.Clear()
+}
+
+// 当前位置：撤销栈顶的序号，撤销栈为空时为最后一个被丢弃的序号（初始为 0）
+func (h *History[S]) top() int {
+	if e, err := h.undo.Peek(); err == nil {
+		return e.seq
+	}
+	return h.trimmed
+}
+
+// Undo 撤销最近的一项，返回它的标签
+func (h *History[S]) Undo() (string, error) {
+	if !h.txs.IsEmpty() {
+		return "", ErrInTransaction
+	}
+	e, err := h.undo.Peek()
+	if err != nil {
+		return "", ErrNothingToUndo
+	}
+	state, err := undoAll(h.state, e.cmds)
+	if err != nil {
+		return "", err
+	}
+	h.state = state
+	h.undo.Pop()
+	h.redo.Push(e)
+	return e.label, nil
+}
+
+// Redo 重做最近撤销的一项，返回它的标签
+func (h *History[S]) Redo() (string, error) {
+	if !h.txs.IsEmpty() {
+		return "", ErrInTransaction
+	}
+	e, err := h.redo.Peek()
+	if err != nil {
+		return "", ErrNothingToRedo
+	}
+	state, err := doAll(h.state, e.cmds)
+	if err != nil {
+		return "", err
+	}
+	h.state = state
+	h.redo.Pop()
+	h.undo.Push(e)
+	return e.label, nil
+}
+
+// 按顺序执行所有命令，中途失败时把已执行的部分撤销，保证要么全部生效要么都不生效
+func doAll[S any](state S, cmds []Command[S]) (S, error) {
+	orig := state
+	for i, cmd := range cmds {
+		next, err := cmd.Do(state)
+		if err != nil {
+			if _, rerr := undoAll(state, cmds[:i]); rerr != nil {
+				return orig, errors.Join(err, rerr)
+			}
+			return orig, err
+		}
+		state = next
+	}
+	return state, nil
+}
+
+// 按逆序撤销所有命令，中途失败时把已撤销的部分重新执行
+func undoAll[S any](state S, cmds []Command[S]) (S, error) {
+	orig := state
+	for i := len(cmds) - 1; i >= 0; i-- {
+		prev, err := cmds[i].Undo(state)
+		if err != nil {
+			if _, rerr := doAll(state, cmds[i+1:]); rerr != nil {
+				return orig, errors.Join(err, rerr)
+			}
+			return orig, err
+		}
+		state = prev
+	}
+	return state, nil
+}
+
+// Begin 开始一个事务，之后 Do 的命令都属于该事务。事务可以嵌套，嵌套事务的标签同样被忽略
+func (h *History[S]) Begin(label string) {
+	h.txs.Push(&group[S]{label: label})
+}
+
+// Commit 提交最内层事务：嵌套事务并入外层，最外层事务作为一项记录到历史中
+func (h *History[S]) Commit() error {
+	tx, err := h.txs.Pop()
+	if err != nil {
+		return ErrNoTransaction
+	}
+	if outer, err := h.txs.Peek(); err == nil {
+		outer.cmds = append(outer.cmds, tx.cmds...)
+		return nil
+	}
+	if len(tx.cmds) > 0 {
+		h.record(tx.label, tx.cmds)
+	}
+	return nil
+}
+
+// Rollback 撤销并丢弃最内层事务中已执行的命令
+func (h *History[S]) Rollback() error {
+	tx, err := h.txs.Peek()
+	if err != nil {
+		return ErrNoTransaction
+	}
+	state, err := undoAll(h.state, tx.cmds)
+	if err != nil {
+		return err
+	}
+	h.state = state
+	h.txs.Pop()
+	return nil
+}
+
+// Checkpoint 给当前位置起名，同名检查点会被覆盖
+func (h *History[S]) Checkpoint(name string) error {
+	if !h.txs.IsEmpty() {
+		return ErrInTransaction
+	}
+	h.checkpoints[name] = h.top()
+	return nil
+}
+
+// Goto 撤销或重做到检查点所在的位置
+func (h *History[S]) Goto(name string) error {
+	if !h.txs.IsEmpty() {
+		return ErrInTransaction
+	}
+	seq, ok := h.checkpoints[name]
+	if !ok {
+		return ErrUnknownCheckpoint
+	}
+	if seq < h.trimmed {
+		return ErrCheckpointTrimmed
+	}
+	for h.top() > seq {
+		if _, err := h.Undo(); err != nil {
+			return err
+		}
+	}
+	for h.top() < seq {
+		if _, err := h.Redo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 配置编辑器的状态
+type Config struct {
+	FeeBps int
+	Owner  string
+}
+
+// 修改手续费的命令，执行时记住旧值以便撤销
+type setFee struct {
+	fee, old int
+}
+
+func (c *setFee) Do(cfg Config) (Config, error) {
+	if c.fee < 0 || c.fee > 10000 {
+		return cfg, errors.New("fee out of range")
+	}
+	c.old = cfg.FeeBps
+	cfg.FeeBps = c.fee
+	return cfg, nil
+}
+
+func (c *setFee) Undo(cfg Config) (Config, error) {
+	cfg.FeeBps = c.old
+	return cfg, nil
+}
+
+func setOwner(owner string) Command[Config] {
+	var old string
+	return NewCommand(
+		func(cfg Config) (Config, error) {
+			old, cfg.Owner = cfg.Owner, owner
+			return cfg, nil
+		},
+		func(cfg Config) (Config, error) {
+			cfg.Owner = old
+			return cfg, nil
+		},
+	)
+}
+
+func main() {
+	h := NewHistory(Config{FeeBps: 30, Owner: "alice"}, 10)
+
+	h.Do("fee 50", &setFee{fee: 50})
+	h.Checkpoint("v1")
+	h.Do("owner bob", setOwner("bob"))
+	fmt.Println(h.State()) // {50 bob}
+
+	label, _ := h.Undo()
+	fmt.Println("撤销", label, h.State()) // 撤销 owner bob {50 alice}
+	label, _ = h.Redo()
+	fmt.Println("重做", label, h.State()) // 重做 owner bob {50 bob}
+
+	// 失败的命令不会改变状态
+	fmt.Println(h.Do("fee 20000", &setFee{fee: 20000}), h.State())
+
+	// 事务整体撤销
+	h.Begin("migrate")
+	h.Do("", &setFee{fee: 10})
+	h.Do("", setOwner("carol"))
+	h.Commit()
+	fmt.Println(h.State()) // {10 carol}
+	label, _ = h.Undo()
+	fmt.Println("撤销", label, h.State()) // 撤销 migrate {50 bob}
+
+	// 回滚事务
+	h.Begin("experiment")
+	h.Do("", &setFee{fee: 99})
+	h.Rollback()
+	fmt.Println("回滚后", h.State()) // 回滚后 {50 bob}
+
+	// 跳回检查点
+	h.Goto("v1")
+	fmt.Println("检查点 v1", h.State()) // 检查点 v1 {50 alice}
+
+	// 超出深度的历史被丢弃
+	small := NewHistory(0, 3)
+	small.Checkpoint("start")
+	for i := 1; i <= 5; i++ {
+		n := i
+		small.Do(fmt.Sprint("+", n), NewCommand(
+			func(s int) (int, error) { return s + n, nil },
+			func(s int) (int, error) { return s - n, nil },
+		))
+	}
+	for small.CanUndo() {
+		small.Undo()
+	}
+	fmt.Println(small.State(), small.Goto("start")) // 3 checkpoint is beyond history depth
+}
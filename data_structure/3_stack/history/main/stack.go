@@ -0,0 +1,81 @@
+package main
+
+import "errors"
+
+// 通用栈，完整实现见 ../../main/stack.go，这里只保留撤销/重做历史用到的方法
+
+// 栈的最小容量，低于该容量时不再收缩
+const minCapacity = 8
+
+// ErrEmpty 对空栈执行 Pop/Peek 时返回
+var ErrEmpty = errors.New("stack is empty")
+
+type Stack[T any] struct {
+	top  int
+	data []T
+}
+
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{
+		top:  -1,
+		data: make([]T, 0),
+	}
+}
+
+func (s *Stack[T]) Push(val T) {
+	s.top++
+	if len(s.data) > s.top {
+		s.data[s.top] = val
+	} else {
+		s.data = append(s.data, val)
+	}
+}
+
+func (s *Stack[T]) Pop() (T, error) {
+	var zero T
+	if s.top == -1 {
+		return zero, ErrEmpty
+	}
+	val := s.data[s.top]
+	s.data[s.top] = zero // 清除引用，避免阻止 GC 回收
+	s.top--
+	s.shrink()
+	return val, nil
+}
+
+func (s *Stack[T]) Peek() (T, error) {
+	if s.top == -1 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return s.data[s.top], nil
+}
+
+func (s *Stack[T]) IsEmpty() bool {
+	return s.top == -1
+}
+
+func (s *Stack[T]) Size() int {
+	return s.top + 1
+}
+
+// Clear 清空栈并释放底层数组
+func (s *Stack[T]) Clear() {
+	s.top = -1
+	s.data = make([]T, 0)
+}
+
+// 使用量降到容量的 1/4 以下时，把容量减半，
+// 与 append 的倍增配合，避免在临界点附近反复扩缩容
+func (s *Stack[T]) shrink() {
+	c := cap(s.data)
+	for c > minCapacity && s.Size() < c/4 {
+		c /= 2
+	}
+	if c == cap(s.data) {
+		return
+	}
+	data := make([]T, s.Size(), c)
+	copy(data, s.data[:s.Size()])
+	s.data = data
+}
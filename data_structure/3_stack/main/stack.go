@@ -1,20 +1,33 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
-type Stack struct {
+// 栈的最小容量，低于该容量时不再收缩
+const minCapacity = 8
+
+var (
+	// ErrEmpty 对空栈执行 Pop/Peek 时返回
+	ErrEmpty = errors.New("stack is empty")
+	// ErrNegativeCount PopN 的 n 为负数时返回
+	ErrNegativeCount = errors.New("negative count")
+)
+
+type Stack[T any] struct {
 	top  int
-	data []int
+	data []T
 }
 
-func NewStack() *Stack {
-	return &Stack{
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{
 		top:  -1,
-		data: make([]int, 0),
+		data: make([]T, 0),
 	}
 }
 
-func (s *Stack) Push(val int) {
+func (s *Stack[T]) Push(val T) {
 	s.top++
 	if len(s.data) > s.top {
 		s.data[s.top] = val
@@ -23,40 +36,133 @@ func (s *Stack) Push(val int) {
 	}
 }
 
-func (s *Stack) Pop() int {
+func (s *Stack[T]) Pop() (T, error) {
+	var zero T
 	if s.top == -1 {
-		panic("stack is empty")
+		return zero, ErrEmpty
 	}
 	val := s.data[s.top]
+	s.data[s.top] = zero // 清除引用，避免阻止 GC 回收
 	s.top--
-	return val
+	s.shrink()
+	return val, nil
+}
+
+// PopN 依次弹出 n 个元素，返回的切片中第一个元素是原来的栈顶。
+// 栈中元素不足 n 个时不弹出任何元素并返回 ErrEmpty
+func (s *Stack[T]) PopN(n int) ([]T, error) {
+	if n < 0 {
+		return nil, ErrNegativeCount
+	}
+	if n > s.Size() {
+		return nil, ErrEmpty
+	}
+	var zero T
+	result := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, s.data[s.top])
+		s.data[s.top] = zero
+		s.top--
+	}
+	s.shrink()
+	return result, nil
 }
 
-func (s *Stack) Peek() int {
+func (s *Stack[T]) Peek() (T, error) {
 	if s.top == -1 {
-		panic("stack is empty")
+		var zero T
+		return zero, ErrEmpty
 	}
-	return s.data[s.top]
+	return s.data[s.top], nil
 }
 
-func (s *Stack) IsEmpty() bool {
+func (s *Stack[T]) IsEmpty() bool {
 	return s.top == -1
 }
 
-func (s *Stack) Size() int {
+func (s *Stack[T]) Size() int {
 	return s.top + 1
 }
 
+// Cap 返回底层数组的容量
+func (s *Stack[T]) Cap() int {
+	return cap(s.data)
+}
+
+// Clear 清空栈并释放底层数组
+func (s *Stack[T]) Clear() {
+	s.top = -1
+	s.data = make([]T, 0)
+}
+
+// Clone 返回栈的一个副本，两者互不影响
+func (s *Stack[T]) Clone() *Stack[T] {
+	data := make([]T, s.Size())
+	copy(data, s.data[:s.Size()])
+	return &Stack[T]{
+		top:  s.top,
+		data: data,
+	}
+}
+
+// 使用量降到容量的 1/4 以下时，把容量减半（PopN 后可能连续减半多次），
+// 与 append 的倍增配合，避免在临界点附近反复扩缩容
+func (s *Stack[T]) shrink() {
+	c := cap(s.data)
+	for c > minCapacity && s.Size() < c/4 {
+		c /= 2
+	}
+	if c == cap(s.data) {
+		return
+	}
+	data := make([]T, s.Size(), c)
+	copy(data, s.data[:s.Size()])
+	s.data = data
+}
+
 func main() {
-	stack := NewStack()
+	stack := NewStack[int]()
 	stack.Push(1)
 	stack.Push(2)
 	stack.Push(3)
 
-	fmt.Println("栈顶元素：", stack.Peek())
-	fmt.Println("出栈元素：", stack.Pop())
-	fmt.Println("出栈元素：", stack.Pop())
-	fmt.Println("栈顶元素：", stack.Peek())
+	top, _ := stack.Peek()
+	fmt.Println("栈顶元素：", top)
+	val, _ := stack.Pop()
+	fmt.Println("出栈元素：", val)
+	val, _ = stack.Pop()
+	fmt.Println("出栈元素：", val)
+	top, _ = stack.Peek()
+	fmt.Println("栈顶元素：", top)
 	fmt.Println("栈是否为空：", stack.IsEmpty())
 	fmt.Println("栈中元素个数：", stack.Size())
+
+	stack.Pop()
+	if _, err := stack.Pop(); errors.Is(err, ErrEmpty) {
+		fmt.Println("空栈出栈：", err)
+	}
+
+	// 突发写入后容量会随着出栈自动收缩
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+	}
+	fmt.Println("突发写入后容量：", stack.Cap())
+	vals, _ := stack.PopN(995)
+	fmt.Println("弹出个数：", len(vals), "剩余：", stack.Size(), "容量：", stack.Cap())
+	if _, err := stack.PopN(-1); errors.Is(err, ErrNegativeCount) {
+		fmt.Println("弹出负数个：", err)
+	}
+
+	clone := stack.Clone()
+	clone.Push(100)
+	fmt.Println("原栈大小：", stack.Size(), "副本大小：", clone.Size())
+
+	stack.Clear()
+	fmt.Println("清空后：", stack.IsEmpty(), stack.Cap())
+
+	words := NewStack[string]()
+	words.Push("hello")
+	words.Push("world")
+	w, _ := words.Pop()
+	fmt.Println("字符串栈出栈：", w)
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// 能在 O(1) 时间内返回最小值和最大值的栈。
+// 每个元素入栈时，同时记录入栈后整个栈的最小值和最大值；
+// 由于栈只在顶部变化，栈顶记录的就是当前的最值，出栈后下一个元素记录的最值自然恢复有效。
+
+type entry[T any] struct {
+	val T
+	min T // 从栈底到该元素为止的最小值
+	max T // 从栈底到该元素为止的最大值
+}
+
+type MinMaxStack[T cmp.Ordered] struct {
+	stack *Stack[entry[T]]
+}
+
+func NewMinMaxStack[T cmp.Ordered]() *MinMaxStack[T] {
+	return &MinMaxStack[T]{stack: NewStack[entry[T]]()}
+}
+
+func (s *MinMaxStack[T]) Push(val T) {
+	e := entry[T]{val, val, val}
+	if top, err := s.stack.Peek(); err == nil {
+		e.min = min(top.min, val)
+		e.max = max(top.max, val)
+	}
+	s.stack.Push(e)
+}
+
+func (s *MinMaxStack[T]) Pop() (T, error) {
+	e, err := s.stack.Pop()
+	return e.val, err
+}
+
+func (s *MinMaxStack[T]) Peek() (T, error) {
+	e, err := s.stack.Peek()
+	return e.val, err
+}
+
+// Min 返回栈中的最小值
+func (s *MinMaxStack[T]) Min() (T, error) {
+	e, err := s.stack.Peek()
+	return e.min, err
+}
+
+// Max 返回栈中的最大值
+func (s *MinMaxStack[T]) Max() (T, error) {
+	e, err := s.stack.Peek()
+	return e.max, err
+}
+
+func (s *MinMaxStack[T]) IsEmpty() bool {
+	return s.stack.IsEmpty()
+}
+
+func (s *MinMaxStack[T]) Size() int {
+	return s.stack.Size()
+}
+
+func main() {
+	s := NewMinMaxStack[int]()
+	for _, v := range []int{5, 2, 8, 1, 9} {
+		s.Push(v)
+		lo, _ := s.Min()
+		hi, _ := s.Max()
+		fmt.Printf("入栈 %d：最小值 %d，最大值 %d\n", v, lo, hi)
+	}
+	for !s.IsEmpty() {
+		v, _ := s.Pop()
+		lo, err := s.Min()
+		hi, _ := s.Max()
+		if err != nil {
+			fmt.Printf("出栈 %d：%v\n", v, err)
+			continue
+		}
+		fmt.Printf("出栈 %d：最小值 %d，最大值 %d\n", v, lo, hi)
+	}
+}
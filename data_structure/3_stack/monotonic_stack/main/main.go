@@ -0,0 +1,32 @@
+package main
+
+import "fmt"
+
+func main() {
+	data := []int{2, 1, 2, 4, 3}
+	fmt.Println("下一个更大元素：", NextGreater(data)) // [3 2 3 -1 -1]
+	fmt.Println("下一个更小元素：", NextSmaller(data)) // [1 -1 -1 4 -1]
+	fmt.Println("上一个更大元素：", PrevGreater(data)) // [-1 0 -1 -1 3]
+	fmt.Println("上一个更小元素：", PrevSmaller(data)) // [-1 -1 1 2 2]
+
+	area, left, right := LargestRectangle([]int{2, 1, 5, 6, 2, 3})
+	fmt.Println("最大矩形：", area, left, right) // 10 2 4
+
+	prices := []int{100, 80, 60, 70, 60, 75, 85}
+	fmt.Println("股票跨度：", StockSpans(prices)) // [1 1 1 2 1 4 6]
+	spanner := NewStockSpanner[int]()
+	for _, p := range prices {
+		fmt.Print(spanner.Next(p), " ")
+	}
+	fmt.Println() // 1 1 1 2 1 4 6
+
+	stream := NewNextGreaterStream[int]()
+	for _, v := range data {
+		for _, r := range stream.Push(v) {
+			fmt.Printf("下标 %d（值 %d）的下一个更大元素在下标 %d\n", r.Index, r.Value, r.NextIndex)
+		}
+	}
+	for _, r := range stream.Flush() {
+		fmt.Printf("下标 %d（值 %d）没有更大元素\n", r.Index, r.Value)
+	}
+}
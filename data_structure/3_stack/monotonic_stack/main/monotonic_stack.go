@@ -0,0 +1,177 @@
+package main
+
+import "cmp"
+
+// 单调栈：栈中保存元素下标，对应的值从栈底到栈顶保持单调。
+// 新元素入栈前，弹出所有破坏单调性的栈顶元素——对这些被弹出的元素来说，新元素就是它们右侧第一个“更大/更小”的元素；
+// 弹出结束后的栈顶则是新元素左侧第一个“更大/更小”的元素。每个下标最多入栈出栈各一次，整体 O(n)。
+
+// NextGreater 返回每个元素右侧第一个严格更大元素的下标，不存在时为 -1
+func NextGreater[T cmp.Ordered](data []T) []int {
+	return nextBy(data, func(top, cur T) bool { return top < cur })
+}
+
+// NextSmaller 返回每个元素右侧第一个严格更小元素的下标，不存在时为 -1
+func NextSmaller[T cmp.Ordered](data []T) []int {
+	return nextBy(data, func(top, cur T) bool { return top > cur })
+}
+
+// PrevGreater 返回每个元素左侧第一个严格更大元素的下标，不存在时为 -1
+func PrevGreater[T cmp.Ordered](data []T) []int {
+	return prevBy(data, func(top, cur T) bool { return top <= cur })
+}
+
+// PrevSmaller 返回每个元素左侧第一个严格更小元素的下标，不存在时为 -1
+func PrevSmaller[T cmp.Ordered](data []T) []int {
+	return prevBy(data, func(top, cur T) bool { return top >= cur })
+}
+
+// pop(top, cur) 为 true 时，当前元素 cur 是栈顶元素 top 要找的答案
+func nextBy[T any](data []T, pop func(top, cur T) bool) []int {
+	result := make([]int, len(data))
+	s := NewStack[int]()
+	for i, v := range data {
+		result[i] = -1
+		for !s.IsEmpty() {
+			top, _ := s.Peek()
+			if !pop(data[top], v) {
+				break
+			}
+			s.Pop()
+			result[top] = i
+		}
+		s.Push(i)
+	}
+	return result
+}
+
+// pop(top, cur) 为 true 时，栈顶元素 top 不可能是 cur 及其右侧元素的答案
+func prevBy[T any](data []T, pop func(top, cur T) bool) []int {
+	result := make([]int, len(data))
+	s := NewStack[int]()
+	for i, v := range data {
+		for !s.IsEmpty() {
+			top, _ := s.Peek()
+			if !pop(data[top], v) {
+				break
+			}
+			s.Pop()
+		}
+		result[i] = -1
+		if top, err := s.Peek(); err == nil {
+			result[i] = top
+		}
+		s.Push(i)
+	}
+	return result
+}
+
+// LargestRectangle 求柱状图中最大矩形的面积，返回面积及矩形覆盖的下标区间 [left, right)
+func LargestRectangle(heights []int) (area, left, right int) {
+	s := NewStack[int]() // 高度单调递增的下标
+	for i := 0; i <= len(heights); i++ {
+		h := 0 // 末尾追加一个高度为 0 的哨兵，把栈中剩余元素全部弹出
+		if i < len(heights) {
+			h = heights[i]
+		}
+		for !s.IsEmpty() {
+			top, _ := s.Peek()
+			if heights[top] < h {
+				break
+			}
+			s.Pop()
+			// 以 heights[top] 为高的矩形向左延伸到新栈顶之后，向右延伸到 i 之前
+			l := 0
+			if prev, err := s.Peek(); err == nil {
+				l = prev + 1
+			}
+			if a := heights[top] * (i - l); a > area {
+				area, left, right = a, l, i
+			}
+		}
+		s.Push(i)
+	}
+	return area, left, right
+}
+
+// StockSpans 对每一天求股票跨度：从当天向前连续价格不高于当天价格的天数（含当天）
+func StockSpans[T cmp.Ordered](prices []T) []int {
+	prev := PrevGreater(prices)
+	spans := make([]int, len(prices))
+	for i, p := range prev {
+		spans[i] = i - p
+	}
+	return spans
+}
+
+// StockSpanner 在价格流上逐个计算股票跨度
+type StockSpanner[T cmp.Ordered] struct {
+	prices *Stack[T]   // 价格单调递减
+	spans  *Stack[int] // 与 prices 对应的跨度
+}
+
+func NewStockSpanner[T cmp.Ordered]() *StockSpanner[T] {
+	return &StockSpanner[T]{prices: NewStack[T](), spans: NewStack[int]()}
+}
+
+// Next 加入当天价格，返回当天的跨度
+func (s *StockSpanner[T]) Next(price T) int {
+	span := 1
+	for !s.prices.IsEmpty() {
+		top, _ := s.prices.Peek()
+		if top > price {
+			break
+		}
+		s.prices.Pop()
+		n, _ := s.spans.Pop()
+		span += n
+	}
+	s.prices.Push(price)
+	s.spans.Push(span)
+	return span
+}
+
+// Resolved 数据流中已经确定答案的一个元素
+type Resolved[T any] struct {
+	Index     int // 元素下标
+	Value     T   // 元素值
+	NextIndex int // 右侧第一个更大元素的下标，Flush 返回的元素为 -1
+}
+
+// NextGreaterStream 在数据流上求下一个更大元素：
+// 每加入一个元素，就返回因它而确定答案的那些旧元素
+type NextGreaterStream[T cmp.Ordered] struct {
+	pending *Stack[Resolved[T]] // 仍在等待答案的元素，值单调不增
+	next    int                 // 下一个元素的下标
+}
+
+func NewNextGreaterStream[T cmp.Ordered]() *NextGreaterStream[T] {
+	return &NextGreaterStream[T]{pending: NewStack[Resolved[T]]()}
+}
+
+// Push 加入一个元素
+func (s *NextGreaterStream[T]) Push(v T) []Resolved[T] {
+	var resolved []Resolved[T]
+	for !s.pending.IsEmpty() {
+		top, _ := s.pending.Peek()
+		if top.Value >= v {
+			break
+		}
+		s.pending.Pop()
+		top.NextIndex = s.next
+		resolved = append(resolved, top)
+	}
+	s.pending.Push(Resolved[T]{Index: s.next, Value: v, NextIndex: -1})
+	s.next++
+	return resolved
+}
+
+// Flush 结束数据流，返回所有没有更大元素的剩余元素
+func (s *NextGreaterStream[T]) Flush() []Resolved[T] {
+	var resolved []Resolved[T]
+	for !s.pending.IsEmpty() {
+		top, _ := s.pending.Pop()
+		resolved = append(resolved, top)
+	}
+	return resolved
+}
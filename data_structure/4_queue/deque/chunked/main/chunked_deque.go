@@ -0,0 +1,252 @@
+package main
+
+import "errors"
+
+// 分块双端队列，思路与 C++ 的 std::deque 相同：
+// 元素存放在固定大小的块（block）中，另有一张环形的块指针表（map）记录各块的先后顺序。
+// 两端插入只会在块用完时申请新块，按下标访问只需一次除法和一次取模，都是 O(1)。
+// 空出来的块不会立即丢弃，而是放回块池，供后续插入复用，减少内存分配。
+
+const (
+	blockSize   = 64 // 每个块容纳的元素个数
+	maxPoolSize = 16 // 块池中最多保留的空闲块数
+)
+
+var (
+	errEmpty      = errors.New("deque is empty")
+	errOutOfRange = errors.New("index out of range")
+)
+
+type block[T any] [blockSize]T
+
+// Deque 分块双端队列
+type Deque[T any] struct {
+	blocks []*block[T] // 环形块指针表，长度始终为 2 的幂
+	first  int         // 第一个块在 blocks 中的位置
+	nblock int         // 正在使用的块数
+	offset int         // 第一个元素在第一个块中的偏移
+	length int         // 元素个数
+	pool   []*block[T] // 空闲块池
+}
+
+// NewDeque 创建一个空的分块双端队列
+func NewDeque[T any]() *Deque[T] {
+	return &Deque[T]{blocks: make([]*block[T], 4)}
+}
+
+// Len 返回元素个数
+func (d *Deque[T]) Len() int {
+	return d.length
+}
+
+// IsEmpty 判断队列是否为空
+func (d *Deque[T]) IsEmpty() bool {
+	return d.length == 0
+}
+
+// 返回第 i 个正在使用的块
+func (d *Deque[T]) blockAt(i int) *block[T] {
+	return d.blocks[(d.first+i)&(len(d.blocks)-1)]
+}
+
+// 返回第 i 个元素所在的槽位
+func (d *Deque[T]) slot(i int) *T {
+	pos := d.offset + i
+	return &d.blockAt(pos / blockSize)[pos%blockSize]
+}
+
+// 从块池取出一个块，池为空时新建
+func (d *Deque[T]) getBlock() *block[T] {
+	if n := len(d.pool); n > 0 {
+		b := d.pool[n-1]
+		d.pool = d.pool[:n-1]
+		return b
+	}
+	return new(block[T])
+}
+
+// 归还一个块，调用者需保证块内元素已清零
+func (d *Deque[T]) putBlock(b *block[T]) {
+	if len(d.pool) < maxPoolSize {
+		d.pool = append(d.pool, b)
+	}
+}
+
+// 块指针表已满时扩容为两倍，并把使用中的块重新排列到开头
+func (d *Deque[T]) growMap() {
+	if d.nblock < len(d.blocks) {
+		return
+	}
+	blocks := make([]*block[T], len(d.blocks)*2)
+	for i := 0; i < d.nblock; i++ {
+		blocks[i] = d.blockAt(i)
+	}
+	d.blocks = blocks
+	d.first = 0
+}
+
+// PushBack 在尾部插入元素
+func (d *Deque[T]) PushBack(v T) {
+	if d.offset+d.length == d.nblock*blockSize {
+		d.growMap()
+		d.blocks[(d.first+d.nblock)&(len(d.blocks)-1)] = d.getBlock()
+		d.nblock++
+	}
+	*d.slot(d.length) = v
+	d.length++
+}
+
+// PushFront 在头部插入元素
+func (d *Deque[T]) PushFront(v T) {
+	if d.offset == 0 {
+		d.growMap()
+		d.first = (d.first - 1) & (len(d.blocks) - 1)
+		d.blocks[d.first] = d.getBlock()
+		d.nblock++
+		d.offset = blockSize
+	}
+	d.offset--
+	d.length++
+	*d.slot(0) = v
+}
+
+// PopFront 弹出头部元素
+func (d *Deque[T]) PopFront() (T, error) {
+	var zero T
+	if d.length == 0 {
+		return zero, errEmpty
+	}
+	s := d.slot(0)
+	v := *s
+	*s = zero
+	d.offset++
+	d.length--
+	if d.offset == blockSize || d.length == 0 {
+		d.releaseFront()
+	}
+	return v, nil
+}
+
+// PopBack 弹出尾部元素
+func (d *Deque[T]) PopBack() (T, error) {
+	var zero T
+	if d.length == 0 {
+		return zero, errEmpty
+	}
+	s := d.slot(d.length - 1)
+	v := *s
+	*s = zero
+	d.length--
+	if d.length == 0 {
+		d.releaseFront()
+	} else if (d.offset+d.length)%blockSize == 0 {
+		// 最后一个块已经空了
+		last := (d.first + d.nblock - 1) & (len(d.blocks) - 1)
+		d.putBlock(d.blocks[last])
+		d.blocks[last] = nil
+		d.nblock--
+	}
+	return v, nil
+}
+
+// 头部块用完或队列变空时，归还头部的空块
+func (d *Deque[T]) releaseFront() {
+	if d.length == 0 {
+		// 队列为空，归还所有块并重置偏移
+		for d.nblock > 0 {
+			d.putBlock(d.blocks[d.first])
+			d.blocks[d.first] = nil
+			d.first = (d.first + 1) & (len(d.blocks) - 1)
+			d.nblock--
+		}
+		d.offset = 0
+		return
+	}
+	d.putBlock(d.blocks[d.first])
+	d.blocks[d.first] = nil
+	d.first = (d.first + 1) & (len(d.blocks) - 1)
+	d.nblock--
+	d.offset = 0
+}
+
+// At 返回下标 i 处的元素
+func (d *Deque[T]) At(i int) (T, error) {
+	if i < 0 || i >= d.length {
+		var zero T
+		return zero, errOutOfRange
+	}
+	return *d.slot(i), nil
+}
+
+// Set 修改下标 i 处的元素
+func (d *Deque[T]) Set(i int, v T) error {
+	if i < 0 || i >= d.length {
+		return errOutOfRange
+	}
+	*d.slot(i) = v
+	return nil
+}
+
+// Insert 在下标 i 处插入元素，只移动离 i 较近的一侧，最多移动 n/2 个元素
+func (d *Deque[T]) Insert(i int, v T) error {
+	if i < 0 || i > d.length {
+		return errOutOfRange
+	}
+	var zero T
+	if i < d.length/2 {
+		d.PushFront(zero)
+		for k := 0; k < i; k++ {
+			*d.slot(k) = *d.slot(k + 1)
+		}
+	} else {
+		d.PushBack(zero)
+		for k := d.length - 1; k > i; k-- {
+			*d.slot(k) = *d.slot(k - 1)
+		}
+	}
+	*d.slot(i) = v
+	return nil
+}
+
+// Rotate 向右循环移动 n 步（n 为负数时向左），
+// 例如 [1 2 3 4 5] 执行 Rotate(2) 后为 [4 5 1 2 3]
+func (d *Deque[T]) Rotate(n int) {
+	if d.length <= 1 {
+		return
+	}
+	n %= d.length
+	if n < 0 {
+		n += d.length
+	}
+	// 选择移动次数较少的方向
+	if n <= d.length/2 {
+		for ; n > 0; n-- {
+			v, _ := d.PopBack()
+			d.PushFront(v)
+		}
+	} else {
+		for n = d.length - n; n > 0; n-- {
+			v, _ := d.PopFront()
+			d.PushBack(v)
+		}
+	}
+}
+
+// Range 按从头到尾的顺序遍历元素，fn 返回 false 时停止
+func (d *Deque[T]) Range(fn func(i int, v T) bool) {
+	for i := 0; i < d.length; i++ {
+		if !fn(i, *d.slot(i)) {
+			return
+		}
+	}
+}
+
+// Slice 返回所有元素组成的切片
+func (d *Deque[T]) Slice() []T {
+	result := make([]T, 0, d.length)
+	d.Range(func(_ int, v T) bool {
+		result = append(result, v)
+		return true
+	})
+	return result
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// 4_queue/deque 中的链表双端队列，每个元素分配一个节点，仅用于基准对比
+type node struct {
+	value interface{}
+	prev  *node
+	next  *node
+}
+
+type linkedDeque struct {
+	length int
+	head   *node
+	tail   *node
+}
+
+func (d *linkedDeque) pushBack(val interface{}) {
+	n := &node{val, d.tail, nil}
+	if d.tail != nil {
+		d.tail.next = n
+	}
+	d.tail = n
+	if d.head == nil {
+		d.head = n
+	}
+	d.length++
+}
+
+func (d *linkedDeque) popFront() interface{} {
+	n := d.head
+	d.head = n.next
+	if d.head != nil {
+		d.head.prev = nil
+	} else {
+		d.tail = nil
+	}
+	d.length--
+	return n.value
+}
+
+// 基准：先插入 n 个元素再全部弹出，反复进行
+const benchN = 10000
+
+func benchmarkLinked(b *testing.B) {
+	b.ReportAllocs()
+	d := &linkedDeque{}
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchN; j++ {
+			d.pushBack(j + 1000)
+		}
+		for j := 0; j < benchN; j++ {
+			d.popFront()
+		}
+	}
+}
+
+func benchmarkChunked(b *testing.B) {
+	b.ReportAllocs()
+	d := NewDeque[int]()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchN; j++ {
+			d.PushBack(j + 1000)
+		}
+		for j := 0; j < benchN; j++ {
+			d.PopFront()
+		}
+	}
+}
+
+func main() {
+	d := NewDeque[int]()
+	for i := 1; i <= 5; i++ {
+		d.PushBack(i)
+	}
+	d.PushFront(0)
+	fmt.Println(d.Slice()) // [0 1 2 3 4 5]
+
+	d.Insert(3, 100)
+	fmt.Println(d.Slice()) // [0 1 2 100 3 4 5]
+
+	d.Set(0, -1)
+	v, _ := d.At(3)
+	fmt.Println(v) // 100
+
+	d.Rotate(2)
+	fmt.Println(d.Slice()) // [4 5 -1 1 2 100 3]
+	d.Rotate(-2)
+	fmt.Println(d.Slice()) // [-1 1 2 100 3 4 5]
+
+	front, _ := d.PopFront()
+	back, _ := d.PopBack()
+	fmt.Println(front, back, d.Len()) // -1 5 5
+
+	// 跨越多个块的随机访问
+	big := NewDeque[int]()
+	for i := 0; i < 1000; i++ {
+		if i%2 == 0 {
+			big.PushBack(i)
+		} else {
+			big.PushFront(i)
+		}
+	}
+	first, _ := big.At(0)
+	last, _ := big.At(999)
+	fmt.Println(first, last) // 999 998
+
+	linked := testing.Benchmark(benchmarkLinked)
+	chunked := testing.Benchmark(benchmarkChunked)
+	fmt.Println("链表双端队列：", linked, linked.MemString())
+	fmt.Println("分块双端队列：", chunked, chunked.MemString())
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	data := []int{1, 3, -1, -3, 5, 3, 6, 7}
+	fmt.Println(SlidingWindowMax(data, 3)) // [3 3 5 5 6 7]
+	fmt.Println(SlidingWindowMin(data, 3)) // [-1 -3 -3 -3 3 3]
+
+	// 数据流上最近 3 个样本的最大值
+	w := NewCountWindowMax[int](3)
+	for _, v := range data {
+		fmt.Print(w.Push(v), " ")
+	}
+	fmt.Println() // 1 3 3 3 5 5 6 7
+	// 窗口大小必须为正
+	func() {
+		defer func() { fmt.Println("窗口大小为 0：", recover()) }()
+		NewCountWindowMax[int](0)
+	}()
+
+	// 最近 10 秒内的最低价
+	base := time.Unix(0, 0)
+	prices := NewTimeWindowMin[float64](10 * time.Second)
+	prices.Add(base, 12.5)
+	prices.Add(base.Add(4*time.Second), 11.0)
+	prices.Add(base.Add(9*time.Second), 13.0)
+	fmt.Println(prices.Value(base.Add(9 * time.Second)))  // 11 true
+	fmt.Println(prices.Value(base.Add(15 * time.Second))) // 13 true
+	fmt.Println(prices.Value(base.Add(30 * time.Second))) // 0 false
+
+	// 带聚合值的队列
+	q := NewMonotonicQueue[int]()
+	for i, v := range []int{4, 8, 2, 6} {
+		q.Push(int64(i), v)
+	}
+	q.Evict(1)
+	hi, _ := q.Max()
+	lo, _ := q.Min()
+	fmt.Println(q.Count(), q.Sum(), q.Average(), hi, lo) // 3 16 5.333333333333333 8 2
+
+	// 每秒最多 3 个请求
+	limiter := NewRateLimiter(3, time.Second)
+	for i := 0; i < 6; i++ {
+		now := base.Add(time.Duration(i) * 300 * time.Millisecond)
+		fmt.Print(limiter.Allow(now), " ")
+	}
+	fmt.Println() // true true true false true true
+
+	// 最近 5 秒的移动平均
+	avg := NewMovingAverage(5 * time.Second)
+	for i := 0; i < 10; i++ {
+		avg.Add(base.Add(time.Duration(i)*time.Second), float64(i))
+	}
+	fmt.Println(avg.Average(base.Add(9 * time.Second))) // 7
+}
@@ -0,0 +1,130 @@
+package main
+
+import "time"
+
+// 带聚合值的单调队列：在普通 FIFO 样本队列之外，额外维护运行和以及最大、最小两个单调双端队列，
+// 因此窗口内的和、个数、平均值、最大值、最小值都可以 O(1) 得到。
+// 在它之上实现了滑动日志限流器和时间窗口移动平均。
+
+// Number 支持求和的数值类型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// MonotonicQueue 带聚合值的队列，样本需按位置 at 递增的顺序加入
+type MonotonicQueue[T Number] struct {
+	samples *Deque[sample[T]]  // 窗口内的全部样本
+	max     *monotonicDeque[T] // 单调递减，队头为最大值
+	min     *monotonicDeque[T] // 单调递增，队头为最小值
+	sum     T                  // 窗口内样本之和
+}
+
+// NewMonotonicQueue 创建一个空队列
+func NewMonotonicQueue[T Number]() *MonotonicQueue[T] {
+	return &MonotonicQueue[T]{
+		samples: NewDeque[sample[T]](),
+		max:     newMonotonicDeque(greater[T]),
+		min:     newMonotonicDeque(less[T]),
+	}
+}
+
+// Push 在位置 at 加入样本 v
+func (q *MonotonicQueue[T]) Push(at int64, v T) {
+	q.samples.PushBack(sample[T]{at, v})
+	q.max.push(at, v)
+	q.min.push(at, v)
+	q.sum += v
+}
+
+// Evict 淘汰所有位置早于 before 的样本
+func (q *MonotonicQueue[T]) Evict(before int64) {
+	for !q.samples.IsEmpty() {
+		front, _ := q.samples.At(0)
+		if front.at >= before {
+			break
+		}
+		q.samples.PopFront()
+		q.sum -= front.value
+	}
+	if q.samples.IsEmpty() {
+		// 浮点数反复加减会累积舍入误差，窗口清空时归零
+		q.sum = 0
+	}
+	q.max.evict(before)
+	q.min.evict(before)
+}
+
+// Count 返回窗口内样本个数
+func (q *MonotonicQueue[T]) Count() int {
+	return q.samples.Len()
+}
+
+// Sum 返回窗口内样本之和
+func (q *MonotonicQueue[T]) Sum() T {
+	return q.sum
+}
+
+// Average 返回窗口内样本的平均值，窗口为空时返回 0
+func (q *MonotonicQueue[T]) Average() float64 {
+	if q.samples.IsEmpty() {
+		return 0
+	}
+	return float64(q.sum) / float64(q.samples.Len())
+}
+
+// Max 返回窗口内的最大值
+func (q *MonotonicQueue[T]) Max() (T, bool) {
+	return q.max.best()
+}
+
+// Min 返回窗口内的最小值
+func (q *MonotonicQueue[T]) Min() (T, bool) {
+	return q.min.best()
+}
+
+// RateLimiter 滑动日志限流器：任意长度为 span 的时间窗口内最多放行 limit 个请求
+type RateLimiter struct {
+	limit int
+	span  time.Duration
+	log   *MonotonicQueue[int]
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(limit int, span time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, span: span, log: NewMonotonicQueue[int]()}
+}
+
+// Allow 判断 now 时刻的请求是否放行，放行的请求会被记录
+func (r *RateLimiter) Allow(now time.Time) bool {
+	r.log.Evict(now.Add(-r.span).UnixNano() + 1)
+	if r.log.Count() >= r.limit {
+		return false
+	}
+	r.log.Push(now.UnixNano(), 1)
+	return true
+}
+
+// MovingAverage 最近 span 时间内样本的移动平均
+type MovingAverage struct {
+	span    time.Duration
+	samples *MonotonicQueue[float64]
+}
+
+// NewMovingAverage 创建移动平均
+func NewMovingAverage(span time.Duration) *MovingAverage {
+	return &MovingAverage{span: span, samples: NewMonotonicQueue[float64]()}
+}
+
+// Add 加入 t 时刻的样本
+func (m *MovingAverage) Add(t time.Time, v float64) {
+	m.samples.Push(t.UnixNano(), v)
+	m.samples.Evict(t.Add(-m.span).UnixNano() + 1)
+}
+
+// Average 返回 (now-span, now] 区间内样本的平均值
+func (m *MovingAverage) Average(now time.Time) float64 {
+	m.samples.Evict(now.Add(-m.span).UnixNano() + 1)
+	return m.samples.Average()
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"cmp"
+	"time"
+)
+
+// 单调双端队列求滑动窗口最值。
+// 队列中从头到尾的元素保持单调（求最大值时递减，求最小值时递增）：
+// 新元素入队前，先从尾部弹出所有不比它“更优”的元素，因为它们在新元素离开窗口之前都不可能成为最值；
+// 头部元素一旦滑出窗口就从头部弹出。于是队头始终是窗口最值，每个元素最多入队出队各一次，均摊 O(1)。
+
+// 窗口中的一个样本，at 是样本的位置：计数窗口中为序号，时间窗口中为纳秒时间戳
+type sample[T any] struct {
+	at    int64
+	value T
+}
+
+// 单调双端队列
+type monotonicDeque[T any] struct {
+	deque  *Deque[sample[T]]
+	better func(a, b T) bool // a 严格优于 b 时返回 true
+}
+
+func newMonotonicDeque[T any](better func(a, b T) bool) *monotonicDeque[T] {
+	return &monotonicDeque[T]{deque: NewDeque[sample[T]](), better: better}
+}
+
+// 加入新样本，并弹出尾部所有不优于它的样本
+func (m *monotonicDeque[T]) push(at int64, v T) {
+	for !m.deque.IsEmpty() {
+		back, _ := m.deque.At(m.deque.Len() - 1)
+		if m.better(back.value, v) {
+			break
+		}
+		m.deque.PopBack()
+	}
+	m.deque.PushBack(sample[T]{at, v})
+}
+
+// 弹出头部所有位置早于 before 的样本
+func (m *monotonicDeque[T]) evict(before int64) {
+	for !m.deque.IsEmpty() {
+		front, _ := m.deque.At(0)
+		if front.at >= before {
+			break
+		}
+		m.deque.PopFront()
+	}
+}
+
+// 当前最值
+func (m *monotonicDeque[T]) best() (T, bool) {
+	front, err := m.deque.At(0)
+	return front.value, err == nil
+}
+
+func greater[T cmp.Ordered](a, b T) bool { return a > b }
+
+func less[T cmp.Ordered](a, b T) bool { return a < b }
+
+// SlidingWindowMax 返回 data 中每个长度为 k 的窗口的最大值，共 len(data)-k+1 个
+func SlidingWindowMax[T cmp.Ordered](data []T, k int) []T {
+	return slidingWindow(data, k, greater[T])
+}
+
+// SlidingWindowMin 返回 data 中每个长度为 k 的窗口的最小值，共 len(data)-k+1 个
+func SlidingWindowMin[T cmp.Ordered](data []T, k int) []T {
+	return slidingWindow(data, k, less[T])
+}
+
+func slidingWindow[T any](data []T, k int, better func(a, b T) bool) []T {
+	if k <= 0 || k > len(data) {
+		return nil
+	}
+	m := newMonotonicDeque(better)
+	result := make([]T, 0, len(data)-k+1)
+	for i, v := range data {
+		m.push(int64(i), v)
+		m.evict(int64(i - k + 1))
+		if i >= k-1 {
+			best, _ := m.best()
+			result = append(result, best)
+		}
+	}
+	return result
+}
+
+// CountWindow 数据流上最近 n 个样本的最值
+type CountWindow[T any] struct {
+	size int
+	seq  int64
+	m    *monotonicDeque[T]
+}
+
+// NewCountWindowMax 创建求最近 n 个样本最大值的窗口，n 至少为 1
+func NewCountWindowMax[T cmp.Ordered](n int) *CountWindow[T] {
+	return newCountWindow(n, greater[T])
+}
+
+// NewCountWindowMin 创建求最近 n 个样本最小值的窗口，n 至少为 1
+func NewCountWindowMin[T cmp.Ordered](n int) *CountWindow[T] {
+	return newCountWindow(n, less[T])
+}
+
+// n < 1 时 Push 会立即淘汰刚加入的样本，返回的零值看起来像是合法的最值，因此直接拒绝
+func newCountWindow[T any](n int, better func(a, b T) bool) *CountWindow[T] {
+	if n < 1 {
+		panic("sliding window: size must be positive")
+	}
+	return &CountWindow[T]{size: n, m: newMonotonicDeque(better)}
+}
+
+// Push 加入一个样本，返回加入后窗口的最值
+func (w *CountWindow[T]) Push(v T) T {
+	w.m.push(w.seq, v)
+	w.seq++
+	w.m.evict(w.seq - int64(w.size))
+	best, _ := w.m.best()
+	return best
+}
+
+// Value 返回窗口当前的最值，窗口为空时返回 false
+func (w *CountWindow[T]) Value() (T, bool) {
+	return w.m.best()
+}
+
+// TimeWindow 数据流上最近一段时间内样本的最值，样本需按时间顺序加入
+type TimeWindow[T any] struct {
+	span time.Duration
+	m    *monotonicDeque[T]
+}
+
+// NewTimeWindowMax 创建求最近 span 时间内最大值的窗口，span 必须为正
+func NewTimeWindowMax[T cmp.Ordered](span time.Duration) *TimeWindow[T] {
+	return newTimeWindow(span, greater[T])
+}
+
+// NewTimeWindowMin 创建求最近 span 时间内最小值的窗口，span 必须为正
+func NewTimeWindowMin[T cmp.Ordered](span time.Duration) *TimeWindow[T] {
+	return newTimeWindow(span, less[T])
+}
+
+func newTimeWindow[T any](span time.Duration, better func(a, b T) bool) *TimeWindow[T] {
+	if span <= 0 {
+		panic("sliding window: span must be positive")
+	}
+	return &TimeWindow[T]{span: span, m: newMonotonicDeque(better)}
+}
+
+// Add 加入 t 时刻的样本，并淘汰 t-span 及更早的样本
+func (w *TimeWindow[T]) Add(t time.Time, v T) {
+	w.m.push(t.UnixNano(), v)
+	w.m.evict(t.Add(-w.span).UnixNano() + 1)
+}
+
+// Value 返回 (now-span, now] 区间内样本的最值，区间内没有样本时返回 false
+func (w *TimeWindow[T]) Value(now time.Time) (T, bool) {
+	w.m.evict(now.Add(-w.span).UnixNano() + 1)
+	return w.m.best()
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 基于工作窃取的 fork/join 执行器。
+// 每个 Worker 拥有一个 WorkStealingDeque：Fork 出的子任务压入自己的底部，
+// 空闲时先弹出自己的任务，没有任务再随机选择其它 Worker 窃取，因此不需要中心队列。
+// Join 在等待期间不会阻塞线程，而是继续执行本地或窃取来的任务（help-first）。
+
+// Task 一个可以被 Join 的任务
+type Task struct {
+	fn   func(w *Worker)
+	done atomic.Bool
+}
+
+// Worker 执行器中的一个工作线程
+type Worker struct {
+	id    int
+	exec  *Executor
+	deque *WorkStealingDeque[*Task]
+	rnd   *rand.Rand
+}
+
+// Executor fork/join 执行器
+type Executor struct {
+	workers []*Worker
+	inject  chan *Task // 外部提交的根任务
+	stop    atomic.Bool
+	wg      sync.WaitGroup
+}
+
+// NewExecutor 创建一个拥有 n 个 Worker 的执行器，n <= 0 时使用 GOMAXPROCS
+func NewExecutor(n int) *Executor {
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	e := &Executor{inject: make(chan *Task, n)}
+	for i := 0; i < n; i++ {
+		e.workers = append(e.workers, &Worker{
+			id:    i,
+			exec:  e,
+			deque: NewWorkStealingDeque[*Task](64),
+			rnd:   rand.New(rand.NewSource(int64(i) + 1)),
+		})
+	}
+	for _, w := range e.workers {
+		e.wg.Add(1)
+		go w.loop()
+	}
+	return e
+}
+
+// Run 提交一个根任务并阻塞直到它（以及它 Join 的所有子任务）完成
+func (e *Executor) Run(fn func(w *Worker)) {
+	finished := make(chan struct{})
+	e.inject <- &Task{fn: func(w *Worker) {
+		fn(w)
+		close(finished)
+	}}
+	<-finished
+}
+
+// Close 停止所有 Worker，调用前应确保没有正在执行的 Run
+func (e *Executor) Close() {
+	e.stop.Store(true)
+	e.wg.Wait()
+}
+
+// Fork 把 fn 作为子任务压入当前 Worker 的队列，返回的 Task 用于 Join
+func (w *Worker) Fork(fn func(w *Worker)) *Task {
+	t := &Task{fn: fn}
+	w.deque.PushBottom(t)
+	return t
+}
+
+// Join 等待任务完成，等待期间执行其它任务
+func (w *Worker) Join(t *Task) {
+	for !t.done.Load() {
+		if next, ok := w.find(); ok {
+			w.run(next)
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+// ID 返回 Worker 编号
+func (w *Worker) ID() int {
+	return w.id
+}
+
+func (w *Worker) run(t *Task) {
+	t.fn(w)
+	t.done.Store(true)
+}
+
+// 先取本地任务，再尝试从其它 Worker 窃取
+func (w *Worker) find() (*Task, bool) {
+	if t, ok := w.deque.PopBottom(); ok {
+		return t, true
+	}
+	workers := w.exec.workers
+	start := w.rnd.Intn(len(workers))
+	for i := 0; i < len(workers); i++ {
+		victim := workers[(start+i)%len(workers)]
+		if victim == w {
+			continue
+		}
+		if t, ok := victim.deque.Steal(); ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Worker 的主循环
+func (w *Worker) loop() {
+	defer w.exec.wg.Done()
+	idle := 0
+	for !w.exec.stop.Load() {
+		if t, ok := w.find(); ok {
+			w.run(t)
+			idle = 0
+			continue
+		}
+		select {
+		case t := <-w.exec.inject:
+			w.run(t)
+			idle = 0
+			continue
+		default:
+		}
+		// 连续找不到任务时逐步退避，避免空转占满 CPU
+		idle++
+		if idle < 64 {
+			runtime.Gosched()
+		} else {
+			time.Sleep(50 * time.Microsecond)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// 低于该规模的子问题直接串行处理，避免任务过细
+const threshold = 1024
+
+// 并行计算 Merkle 根：左右子树分别作为任务，父节点哈希 = sha256(左 || 右)
+func parallelMerkleRoot(w *Worker, leaves [][]byte) [32]byte {
+	if len(leaves) <= threshold {
+		return merkleRoot(leaves)
+	}
+	mid := split(len(leaves))
+	var left [32]byte
+	t := w.Fork(func(w *Worker) {
+		left = parallelMerkleRoot(w, leaves[:mid])
+	})
+	right := parallelMerkleRoot(w, leaves[mid:])
+	w.Join(t)
+	return sha256.Sum256(append(left[:], right[:]...))
+}
+
+// 串行计算 Merkle 根
+func merkleRoot(leaves [][]byte) [32]byte {
+	if len(leaves) == 1 {
+		return sha256.Sum256(leaves[0])
+	}
+	mid := split(len(leaves))
+	left := merkleRoot(leaves[:mid])
+	right := merkleRoot(leaves[mid:])
+	return sha256.Sum256(append(left[:], right[:]...))
+}
+
+// 按小于 n 的最大 2 的幂切分，保证串行和并行得到同样的树形
+func split(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+// 并行归并排序
+func parallelMergeSort(w *Worker, data, buf []int) {
+	if len(data) <= threshold {
+		sort.Ints(data)
+		return
+	}
+	mid := len(data) / 2
+	t := w.Fork(func(w *Worker) {
+		parallelMergeSort(w, data[:mid], buf[:mid])
+	})
+	parallelMergeSort(w, data[mid:], buf[mid:])
+	w.Join(t)
+
+	// 合并两个有序区间
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(data) {
+		if data[i] <= data[j] {
+			buf[k] = data[i]
+			i++
+		} else {
+			buf[k] = data[j]
+			j++
+		}
+		k++
+	}
+	k += copy(buf[k:], data[i:mid])
+	copy(buf[k:], data[j:])
+	copy(data, buf[:len(data)])
+}
+
+func main() {
+	// 单线程下的基本行为：所有者端是 LIFO，窃取端是 FIFO
+	d := NewWorkStealingDeque[int](2)
+	for i := 1; i <= 5; i++ {
+		d.PushBottom(i)
+	}
+	fmt.Println(d.Steal())     // 1 true
+	fmt.Println(d.PopBottom()) // 5 true
+	fmt.Println(d.Len())       // 3
+
+	e := NewExecutor(0)
+	defer e.Close()
+
+	leaves := make([][]byte, 100000)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("tx-%d", i))
+	}
+	start := time.Now()
+	serial := merkleRoot(leaves)
+	fmt.Println("串行 Merkle 根：", hex.EncodeToString(serial[:8]), time.Since(start))
+
+	var parallel [32]byte
+	start = time.Now()
+	e.Run(func(w *Worker) {
+		parallel = parallelMerkleRoot(w, leaves)
+	})
+	fmt.Println("并行 Merkle 根：", hex.EncodeToString(parallel[:8]), time.Since(start))
+	fmt.Println("结果一致：", serial == parallel)
+
+	data := make([]int, 1000000)
+	for i := range data {
+		data[i] = rand.Int()
+	}
+	start = time.Now()
+	e.Run(func(w *Worker) {
+		parallelMergeSort(w, data, make([]int, len(data)))
+	})
+	fmt.Println("并行归并排序：", time.Since(start), "有序：", sort.IntsAreSorted(data))
+}
@@ -0,0 +1,124 @@
+package main
+
+import "sync/atomic"
+
+// Chase-Lev 工作窃取双端队列，参考 Chase & Lev 的
+// "Dynamic Circular Work-Stealing Deque"（2005）以及 Lê 等人给出的内存模型修正版本（2013）。
+//
+// 队列只有一个所有者（owner），它在底部（bottom）压入和弹出，行为像栈；
+// 其它线程（thief）只能从顶部（top）窃取，行为像队列。
+// 所有者的 PushBottom 完全无需 CAS，只有在队列只剩最后一个元素时 PopBottom 才需要和窃取者竞争 top。
+// Go 的 sync/atomic 提供顺序一致性，所以论文中的内存屏障在这里都是隐含的。
+
+// 环形数组，容量始终是 2 的幂
+type ring[T any] struct {
+	slots []atomic.Pointer[T]
+}
+
+func newRing[T any](size int) *ring[T] {
+	return &ring[T]{slots: make([]atomic.Pointer[T], size)}
+}
+
+func (r *ring[T]) size() int64 {
+	return int64(len(r.slots))
+}
+
+func (r *ring[T]) get(i int64) *T {
+	return r.slots[i&(r.size()-1)].Load()
+}
+
+func (r *ring[T]) put(i int64, v *T) {
+	r.slots[i&(r.size()-1)].Store(v)
+}
+
+// 扩容为原来的两倍，并拷贝 [t, b) 区间的元素
+func (r *ring[T]) grow(b, t int64) *ring[T] {
+	nr := newRing[T](len(r.slots) * 2)
+	for i := t; i < b; i++ {
+		nr.put(i, r.get(i))
+	}
+	return nr
+}
+
+// WorkStealingDeque 工作窃取双端队列
+type WorkStealingDeque[T any] struct {
+	top    atomic.Int64            // 窃取端下标，只增不减
+	bottom atomic.Int64            // 所有者端下标
+	array  atomic.Pointer[ring[T]] // 当前环形数组，扩容时整体替换
+}
+
+// NewWorkStealingDeque 创建一个初始容量为 size 的队列，size 会向上取整为 2 的幂
+func NewWorkStealingDeque[T any](size int) *WorkStealingDeque[T] {
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	d := &WorkStealingDeque[T]{}
+	d.array.Store(newRing[T](n))
+	return d
+}
+
+// PushBottom 在底部压入元素，只能由所有者调用
+func (d *WorkStealingDeque[T]) PushBottom(v T) {
+	b := d.bottom.Load()
+	t := d.top.Load()
+	a := d.array.Load()
+	if b-t > a.size()-1 {
+		// 队列已满，扩容后再发布新数组
+		a = a.grow(b, t)
+		d.array.Store(a)
+	}
+	a.put(b, &v)
+	d.bottom.Store(b + 1)
+}
+
+// PopBottom 从底部弹出元素，只能由所有者调用
+func (d *WorkStealingDeque[T]) PopBottom() (T, bool) {
+	var zero T
+	b := d.bottom.Load() - 1
+	a := d.array.Load()
+	// 先预留 b，使之后的窃取者看到缩小后的 bottom
+	d.bottom.Store(b)
+	t := d.top.Load()
+	if t > b {
+		// 队列为空，恢复 bottom
+		d.bottom.Store(b + 1)
+		return zero, false
+	}
+	v := a.get(b)
+	if t == b {
+		// 只剩最后一个元素，和窃取者竞争 top
+		won := d.top.CompareAndSwap(t, t+1)
+		d.bottom.Store(b + 1)
+		if !won {
+			return zero, false
+		}
+	}
+	return *v, true
+}
+
+// Steal 从顶部窃取元素，可以由任意线程调用。
+// 返回 false 表示队列为空，或者与其他线程竞争失败
+func (d *WorkStealingDeque[T]) Steal() (T, bool) {
+	var zero T
+	t := d.top.Load()
+	b := d.bottom.Load()
+	if t >= b {
+		return zero, false
+	}
+	a := d.array.Load()
+	v := a.get(t)
+	if !d.top.CompareAndSwap(t, t+1) {
+		return zero, false
+	}
+	return *v, true
+}
+
+// Len 返回队列中元素个数的近似值
+func (d *WorkStealingDeque[T]) Len() int {
+	n := d.bottom.Load() - d.top.Load()
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// 线性一致性检查器：并发地对 list 执行随机操作并记录历史，
+// 再用 Wing & Gong 的回溯算法（带 Lowe 的状态缓存）寻找一个合法的顺序执行。
+// 只要能找到一种顺序，使每个操作都落在自己的调用与返回之间、且结果与顺序双端队列一致，
+// 这段历史就是线性一致的。
+
+// 操作类型
+const (
+	opPushFront = iota
+	opPushBack
+	opPopFront
+	opPopBack
+)
+
+var opNames = []string{"pushFront", "pushBack", "popFront", "popBack"}
+
+// 一次操作的记录
+type operation struct {
+	kind int   // 操作类型
+	arg  int   // push 的参数
+	ret  int   // pop 的返回值
+	ok   bool  // pop 是否成功
+	call int64 // 调用时刻（逻辑时钟）
+	done int64 // 返回时刻（逻辑时钟）
+}
+
+func (op operation) String() string {
+	switch op.kind {
+	case opPushFront, opPushBack:
+		return fmt.Sprintf("[%d,%d] %s(%d)", op.call, op.done, opNames[op.kind], op.arg)
+	default:
+		return fmt.Sprintf("[%d,%d] %s() = %d, %v", op.call, op.done, opNames[op.kind], op.ret, op.ok)
+	}
+}
+
+// 启动 workers 个 goroutine，每个执行 opsPerWorker 次随机操作，返回完整历史
+func runConcurrentHistory(workers, opsPerWorker int) []operation {
+	l := newList()
+	var clock atomic.Int64
+	histories := make([][]operation, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(rand.Int63()))
+			for i := 0; i < opsPerWorker; i++ {
+				op := operation{kind: r.Intn(4), arg: w*opsPerWorker + i}
+				op.call = clock.Add(1)
+				switch op.kind {
+				case opPushFront:
+					l.pushFront(op.arg)
+				case opPushBack:
+					l.pushBack(op.arg)
+				case opPopFront:
+					op.ret, op.ok = l.popFront()
+				case opPopBack:
+					op.ret, op.ok = l.popBack()
+				}
+				op.done = clock.Add(1)
+				histories[w] = append(histories[w], op)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var h []operation
+	for _, ops := range histories {
+		h = append(h, ops...)
+	}
+	return h
+}
+
+// 检查历史是否线性一致
+func checkLinearizable(h []operation) bool {
+	linearized := make([]bool, len(h))
+	visited := make(map[string]bool)
+
+	var search func(state []int, remaining int) bool
+	search = func(state []int, remaining int) bool {
+		if remaining == 0 {
+			return true
+		}
+		key := cacheKey(linearized, state)
+		if visited[key] {
+			return false
+		}
+		visited[key] = true
+
+		// 尚未线性化的操作中最早的返回时刻，调用晚于它的操作不能排在下一个
+		minDone := int64(math.MaxInt64)
+		for i, op := range h {
+			if !linearized[i] && op.done < minDone {
+				minDone = op.done
+			}
+		}
+		for i, op := range h {
+			if linearized[i] || op.call > minDone {
+				continue
+			}
+			next, ok := applySequential(state, op)
+			if !ok {
+				continue
+			}
+			linearized[i] = true
+			if search(next, remaining-1) {
+				return true
+			}
+			linearized[i] = false
+		}
+		return false
+	}
+	return search(nil, len(h))
+}
+
+// 在顺序双端队列模型上执行操作，结果与记录不符时返回 false
+func applySequential(state []int, op operation) ([]int, bool) {
+	switch op.kind {
+	case opPushFront:
+		return append([]int{op.arg}, state...), true
+	case opPushBack:
+		next := make([]int, len(state), len(state)+1)
+		copy(next, state)
+		return append(next, op.arg), true
+	case opPopFront:
+		if len(state) == 0 {
+			return state, !op.ok
+		}
+		return state[1:], op.ok && op.ret == state[0]
+	default:
+		if len(state) == 0 {
+			return state, !op.ok
+		}
+		return state[:len(state)-1], op.ok && op.ret == state[len(state)-1]
+	}
+}
+
+// 由已线性化集合和模型状态构造缓存键
+func cacheKey(linearized []bool, state []int) string {
+	var b strings.Builder
+	for _, v := range linearized {
+		if v {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	b.WriteByte('|')
+	for _, v := range state {
+		fmt.Fprintf(&b, "%d,", v)
+	}
+	return b.String()
+}
@@ -3,101 +3,195 @@ package main
 import (
 	"fmt"
 	"sync/atomic"
-	"unsafe"
+)
+
+// 无锁双端队列，算法来自 Maged M. Michael 的
+// "CAS-Based Lock-Free Algorithm for Shared Deques"（2003）。
+//
+// 整个队列的状态由一个锚点（anchor）描述：最左节点、最右节点以及一个状态标记。
+// 锚点是不可变的，每次修改都分配一个新锚点并通过 CAS 整体替换，
+// 因此两端指针和状态总是被同时原子地更新，也不存在 ABA 问题（旧锚点由 GC 保活）。
+//
+// 入队分两步：先用 CAS 把新节点挂到锚点上并把状态置为 rpush/lpush，
+// 再由任意线程“稳定化”（stabilize）——补齐旧端节点指向新节点的指针，然后把状态恢复为 stable。
+// 只有在 stable 状态下才允许新的入队或出队，其它线程遇到非 stable 状态会先帮忙完成稳定化。
+
+// 锚点状态
+const (
+	stable = iota // 所有节点的前后指针都已一致
+	rpush         // 尾部刚插入节点，旧尾节点的 next 可能尚未指向它
+	lpush         // 头部刚插入节点，旧头节点的 prev 可能尚未指向它
 )
 
 // 双向链表节点
 type node struct {
 	val  int
-	prev unsafe.Pointer // 前驱指针，使用 unsafe.Pointer 类型，保证指针操作的原子性
-	next unsafe.Pointer // 后继指针，使用 unsafe.Pointer 类型，保证指针操作的原子性
+	prev atomic.Pointer[node] // 前驱指针
+	next atomic.Pointer[node] // 后继指针
+}
+
+// 锚点，创建后不再修改
+type anchor struct {
+	head   *node // 最左节点
+	tail   *node // 最右节点
+	status int   // 锚点状态
 }
 
 // 双向链表
 type list struct {
-	head unsafe.Pointer // 头节点指针，使用 unsafe.Pointer 类型，保证指针操作的原子性
-	tail unsafe.Pointer // 尾节点指针，使用 unsafe.Pointer 类型，保证指针操作的原子性
-	len  int32          // 链表长度，使用 int32 类型，保证原子操作
+	anchor atomic.Pointer[anchor] // 当前锚点
+	len    atomic.Int32           // 链表长度，仅在操作成功后更新，并发时只是近似值
 }
 
 // 初始化双向链表
 func newList() *list {
-	n := unsafe.Pointer(new(node))
-	return &list{
-		head: n,
-		tail: n,
-		len:  0,
-	}
+	l := &list{}
+	l.anchor.Store(&anchor{status: stable})
+	return l
 }
 
 // 头部添加节点
 func (l *list) pushFront(val int) {
-	newHead := unsafe.Pointer(&node{
-		val:  val,
-		prev: nil,
-		next: l.head,
-	})
-	oldHead := atomic.SwapPointer(&l.head, newHead)
-	(*node)(oldHead).prev = newHead
-	atomic.AddInt32(&l.len, 1)
+	n := &node{val: val}
+	for {
+		a := l.anchor.Load()
+		if a.head == nil {
+			if l.anchor.CompareAndSwap(a, &anchor{head: n, tail: n, status: stable}) {
+				break
+			}
+		} else if a.status == stable {
+			n.next.Store(a.head)
+			na := &anchor{head: n, tail: a.tail, status: lpush}
+			if l.anchor.CompareAndSwap(a, na) {
+				l.stabilizeLeft(na)
+				break
+			}
+		} else {
+			l.stabilize(a)
+		}
+	}
+	l.len.Add(1)
 }
 
 // 尾部添加节点
 func (l *list) pushBack(val int) {
-	newTail := unsafe.Pointer(&node{
-		val:  val,
-		prev: l.tail,
-		next: nil,
-	})
+	n := &node{val: val}
 	for {
-		oldTail := atomic.LoadPointer(&l.tail)
-		if atomic.CompareAndSwapPointer(&(*node)(oldTail).next, nil, newTail) {
-			if atomic.CompareAndSwapPointer(&l.tail, oldTail, newTail) {
-				(*node)(oldTail).next = newTail
-				atomic.AddInt32(&l.len, 1)
-				return
+		a := l.anchor.Load()
+		if a.tail == nil {
+			if l.anchor.CompareAndSwap(a, &anchor{head: n, tail: n, status: stable}) {
+				break
+			}
+		} else if a.status == stable {
+			n.prev.Store(a.tail)
+			na := &anchor{head: a.head, tail: n, status: rpush}
+			if l.anchor.CompareAndSwap(a, na) {
+				l.stabilizeRight(na)
+				break
 			}
+		} else {
+			l.stabilize(a)
 		}
 	}
+	l.len.Add(1)
 }
 
-// 头部弹出节点
-func (l *list) popFront() int {
+// 头部弹出节点，队列为空时返回 false
+func (l *list) popFront() (int, bool) {
 	for {
-		oldHead := atomic.LoadPointer(&l.head)
-		if oldHead == atomic.LoadPointer(&l.tail) {
-			return -1
+		a := l.anchor.Load()
+		if a.head == nil {
+			return 0, false
 		}
-		newHead := (*node)(oldHead).next
-		if atomic.CompareAndSwapPointer((*unsafe.Pointer)(unsafe.Pointer(&l.head)), oldHead, newHead) {
-			(*node)(newHead).prev = nil
-			val := (*node)(oldHead).val
-			atomic.AddInt32(&l.len, -1)
-			return val
+		if a.head == a.tail {
+			if l.anchor.CompareAndSwap(a, &anchor{status: stable}) {
+				l.len.Add(-1)
+				return a.head.val, true
+			}
+		} else if a.status == stable {
+			next := a.head.next.Load()
+			if l.anchor.CompareAndSwap(a, &anchor{head: next, tail: a.tail, status: stable}) {
+				l.len.Add(-1)
+				return a.head.val, true
+			}
+		} else {
+			l.stabilize(a)
 		}
 	}
 }
 
-// 尾部弹出节点
-func (l *list) popBack() int {
+// 尾部弹出节点，队列为空时返回 false
+func (l *list) popBack() (int, bool) {
 	for {
-		oldTail := atomic.LoadPointer(&l.tail)
-		newTail := (*node)(oldTail).prev
-		if oldTail == atomic.LoadPointer(&l.head) {
-			return -1
+		a := l.anchor.Load()
+		if a.tail == nil {
+			return 0, false
 		}
-		if atomic.CompareAndSwapPointer((*unsafe.Pointer)(unsafe.Pointer(&l.tail)), oldTail, newTail) {
-			(*node)(newTail).next = nil
-			val := (*node)(oldTail).val
-			atomic.AddInt32(&l.len, -1)
-			return val
+		if a.head == a.tail {
+			if l.anchor.CompareAndSwap(a, &anchor{status: stable}) {
+				l.len.Add(-1)
+				return a.tail.val, true
+			}
+		} else if a.status == stable {
+			prev := a.tail.prev.Load()
+			if l.anchor.CompareAndSwap(a, &anchor{head: a.head, tail: prev, status: stable}) {
+				l.len.Add(-1)
+				return a.tail.val, true
+			}
+		} else {
+			l.stabilize(a)
 		}
 	}
 }
 
+// 帮助完成一个未完成的入队操作
+func (l *list) stabilize(a *anchor) {
+	if a.status == rpush {
+		l.stabilizeRight(a)
+	} else {
+		l.stabilizeLeft(a)
+	}
+}
+
+// 让旧尾节点的 next 指向新尾节点，然后把锚点恢复为 stable
+func (l *list) stabilizeRight(a *anchor) {
+	prev := a.tail.prev.Load()
+	if l.anchor.Load() != a {
+		return
+	}
+	prevNext := prev.next.Load()
+	if prevNext != a.tail {
+		if l.anchor.Load() != a {
+			return
+		}
+		if !prev.next.CompareAndSwap(prevNext, a.tail) {
+			return
+		}
+	}
+	l.anchor.CompareAndSwap(a, &anchor{head: a.head, tail: a.tail, status: stable})
+}
+
+// 让旧头节点的 prev 指向新头节点，然后把锚点恢复为 stable
+func (l *list) stabilizeLeft(a *anchor) {
+	next := a.head.next.Load()
+	if l.anchor.Load() != a {
+		return
+	}
+	nextPrev := next.prev.Load()
+	if nextPrev != a.head {
+		if l.anchor.Load() != a {
+			return
+		}
+		if !next.prev.CompareAndSwap(nextPrev, a.head) {
+			return
+		}
+	}
+	l.anchor.CompareAndSwap(a, &anchor{head: a.head, tail: a.tail, status: stable})
+}
+
 // 获取链表长度
 func (l *list) lenList() int {
-	return int(atomic.LoadInt32(&l.len))
+	return int(l.len.Load())
 }
 
 func main() {
@@ -108,9 +202,22 @@ func main() {
 	l.pushBack(4)
 	l.pushBack(5)
 	l.pushBack(6)
-	fmt.Println(l.popFront())
-	fmt.Println(l.popFront())
-	fmt.Println(l.popBack())
-	fmt.Println(l.popBack())
-	fmt.Println(l.lenList())
+	fmt.Println(l.popFront()) // 3 true
+	fmt.Println(l.popFront()) // 2 true
+	fmt.Println(l.popBack())  // 6 true
+	fmt.Println(l.popBack())  // 5 true
+	fmt.Println(l.lenList())  // 2
+
+	// 并发记录操作历史，并用线性一致性检查器验证
+	for round := 0; round < 200; round++ {
+		h := runConcurrentHistory(4, 6)
+		if !checkLinearizable(h) {
+			fmt.Println("第", round, "轮历史不满足线性一致性：")
+			for _, op := range h {
+				fmt.Println(op)
+			}
+			return
+		}
+	}
+	fmt.Println("200 轮并发历史均满足线性一致性")
 }
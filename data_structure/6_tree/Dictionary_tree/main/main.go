@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// 随机插入和删除，与 map 对比各项操作的结果
+func check() error {
+	rng := rand.New(rand.NewSource(1))
+	t := NewTrie[int]()
+	want := make(map[string]int)
+	randKey := func() string {
+		b := make([]byte, rng.Intn(6))
+		for i := range b {
+			b[i] = "abc"[rng.Intn(3)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 20000; i++ {
+		k := randKey()
+		if rng.Intn(3) == 0 {
+			_, had := want[k]
+			delete(want, k)
+			if t.Delete(k) != had {
+				return fmt.Errorf("Delete(%q) disagrees with map", k)
+			}
+		} else {
+			_, had := want[k]
+			want[k] = i
+			if t.Insert([]byte(k), i) == had {
+				return fmt.Errorf("Insert(%q) disagrees with map", k)
+			}
+		}
+
+		p := randKey()
+		var keys []string
+		for k := range want {
+			if strings.HasPrefix(k, p) {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		var walked []string
+		t.WalkPrefix(p, func(key string, value int) bool {
+			if want[key] != value {
+				return false
+			}
+			walked = append(walked, key)
+			return true
+		})
+		if !slices.Equal(keys, walked) || t.CountPrefix(p) != len(keys) || t.HasPrefix(p) != (len(keys) > 0) {
+			return fmt.Errorf("prefix %q: walked %v, want %v", p, walked, keys)
+		}
+		longest := -1
+		for k := range want {
+			if strings.HasPrefix(p, k) {
+				longest = max(longest, len(k))
+			}
+		}
+		if lp, v, ok := t.LongestPrefix(p); ok != (longest >= 0) || ok && (len(lp) != longest || want[lp] != v) {
+			return fmt.Errorf("LongestPrefix(%q) = %q", p, lp)
+		}
+	}
+	if t.Len() != len(want) {
+		return fmt.Errorf("Len = %d, want %d", t.Len(), len(want))
+	}
+	// 全部删除后只剩根节点
+	for k := range want {
+		t.Delete(k)
+	}
+	if t.Len() != 0 || len(t.root.children) != 0 {
+		return fmt.Errorf("nodes left after deleting every key")
+	}
+	return nil
+}
+
+func main() {
+	t := NewTrie[int]()
+	for i, w := range []string{"apple", "app", "application", "apply", "banana", "band", "bandana"} {
+		t.Insert(w, i)
+	}
+	fmt.Println(t.Len(), t.CountPrefix("app"), t.CountPrefix("ban"), t.HasPrefix("c")) // 7 4 3 false
+
+	t.WalkPrefix("app", func(key string, value int) bool {
+		fmt.Print(key, "=", value, " ")
+		return true
+	})
+	fmt.Println() // app=1 apple=0 application=2 apply=3
+
+	fmt.Println(t.Delete("app"), t.Delete("app"), t.CountPrefix("app")) // true false 3
+	v, ok := t.Get("app")
+	fmt.Println(v, ok) // 0 false
+
+	// 最长前缀匹配：按命令表解析输入
+	commands := NewTrie[string]()
+	commands.Insert("git", "git")
+	commands.Insert("git remote", "git-remote")
+	commands.Insert("git remote add", "git-remote-add")
+	for _, line := range []string{"git remote add origin url", "git remote -v", "git status", "go build"} {
+		prefix, cmd, ok := commands.LongestPrefix(line)
+		fmt.Printf("%q -> %q %q %v\n", line, prefix, cmd, ok)
+	}
+
+	// 键也可以是 []byte，与 string 的键在同一棵树中
+	hashes := NewTrie[bool]()
+	hashes.Insert([]byte{0xde, 0xad, 0xbe, 0xef}, true)
+	hashes.Insert([]byte{0xde, 0xad, 0x00}, true)
+	fmt.Println(hashes.CountPrefix([]byte{0xde, 0xad}), hashes.HasPrefix("\xde\xad\xbe")) // 2 true
+	// 遍历不会改动前缀切片容量范围内的数据
+	prefix := append(make([]byte, 0, 8), 0xde)
+	hashes.WalkPrefix(prefix, func([]byte, bool) bool { return true })
+	fmt.Println(prefix[:8]) // [222 0 0 0 0 0 0 0]
+
+	if err := check(); err != nil {
+		fmt.Println("检查失败：", err)
+		return
+	}
+	fmt.Println("随机检查通过")
+}
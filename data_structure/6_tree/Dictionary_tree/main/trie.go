@@ -0,0 +1,175 @@
+package main
+
+import "slices"
+
+// 字典树：按 字典树.md 中的设计实现，做了几处改动：
+//   - 边上是字节而不是 rune，string 和 []byte 的键使用同一棵树，按字节比较的顺序就是键的字典序；
+//   - 子节点按边上的字节排序存放，用二分查找代替线性查找，遍历时也就按字典序输出；
+//   - 每个节点记录子树中键的个数，统计前缀只需走到前缀对应的节点，是 O(|prefix|) 而不必遍历子树；
+//   - 删除键时沿路径减少计数，计数减到 0 的子树整个剪掉，树中不会留下没有键的节点。
+
+// Key 字典树的键可以是字符串或字节切片
+type Key interface {
+	~string | ~[]byte
+}
+
+type trieNode[V any] struct {
+	labels   []byte // 各子节点对应的字节，升序
+	children []*trieNode[V]
+	value    V
+	isEnd    bool // 从根节点到这里的路径是否是一个键
+	count    int  // 以这个节点为根的子树中键的个数
+}
+
+// 边上的字节为 c 的子节点，以及它在 children 中应在的位置
+func (n *trieNode[V]) child(c byte) (*trieNode[V], int) {
+	i, found := slices.BinarySearch(n.labels, c)
+	if !found {
+		return nil, i
+	}
+	return n.children[i], i
+}
+
+// Trie 字典树，零值是一棵空树
+type Trie[V any] struct {
+	root trieNode[V]
+}
+
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// 前缀对应的节点，不存在时返回 nil
+func (t *Trie[V]) find[K Key](key K) *trieNode[V] {
+	n := &t.root
+	for i := 0; i < len(key) && n != nil; i++ {
+		n, _ = n.child(key[i])
+	}
+	return n
+}
+
+// Insert 插入键值对，键已经存在时替换值并返回 false
+func (t *Trie[V]) Insert[K Key](key K, value V) bool {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		next, pos := n.child(c)
+		if next == nil {
+			next = &trieNode[V]{}
+			n.labels = slices.Insert(n.labels, pos, c)
+			n.children = slices.Insert(n.children, pos, next)
+		}
+		n = next
+	}
+	n.value = value
+	if n.isEnd {
+		return false
+	}
+	n.isEnd = true
+	// 新键：路径上每个节点的计数加一
+	n = &t.root
+	n.count++
+	for i := 0; i < len(key); i++ {
+		n, _ = n.child(key[i])
+		n.count++
+	}
+	return true
+}
+
+// Get 返回键对应的值
+func (t *Trie[V]) Get[K Key](key K) (V, bool) {
+	n := t.find(key)
+	if n == nil || !n.isEnd {
+		var zero V
+		return zero, false
+	}
+	return n.value, true
+}
+
+// Delete 删除键，键不存在时返回 false
+func (t *Trie[V]) Delete[K Key](key K) bool {
+	n := t.find(key)
+	if n == nil || !n.isEnd {
+		return false
+	}
+	n.isEnd = false
+	var zero V
+	n.value = zero
+	n = &t.root
+	n.count--
+	for i := 0; i < len(key); i++ {
+		next, pos := n.child(key[i])
+		next.count--
+		if next.count == 0 {
+			// 子树中已经没有键，整个剪掉
+			n.labels = slices.Delete(n.labels, pos, pos+1)
+			n.children = slices.Delete(n.children, pos, pos+1)
+			return true
+		}
+		n = next
+	}
+	return true
+}
+
+// Len 返回键的个数
+func (t *Trie[V]) Len() int {
+	return t.root.count
+}
+
+// HasPrefix 判断是否有以 prefix 开头的键
+func (t *Trie[V]) HasPrefix[K Key](prefix K) bool {
+	return t.CountPrefix(prefix) > 0
+}
+
+// CountPrefix 返回以 prefix 开头的键的个数
+func (t *Trie[V]) CountPrefix[K Key](prefix K) int {
+	n := t.find(prefix)
+	if n == nil {
+		return 0
+	}
+	return n.count
+}
+
+// WalkPrefix 按字典序遍历以 prefix 开头的键值对，fn 返回 false 时停止
+func (t *Trie[V]) WalkPrefix[K Key](prefix K, fn func(key K, value V) bool) {
+	n := t.find(prefix)
+	if n == nil {
+		return
+	}
+	buf := append([]byte(nil), prefix...) // K 为 []byte 时 []byte(prefix) 不复制，会写到调用者的数组中
+	walk(n, &buf, func(key []byte, value V) bool {
+		return fn(K(string(key)), value)
+	})
+}
+
+// 先序遍历，buf 是从根节点到 n 的路径
+func walk[V any](n *trieNode[V], buf *[]byte, fn func(key []byte, value V) bool) bool {
+	if n.isEnd && !fn(*buf, n.value) {
+		return false
+	}
+	for i, child := range n.children {
+		*buf = append(*buf, n.labels[i])
+		ok := walk(child, buf, fn)
+		*buf = (*buf)[:len(*buf)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// LongestPrefix 返回树中是 key 的前缀的最长的键及其值，例如按路由表或命令表匹配
+func (t *Trie[V]) LongestPrefix[K Key](key K) (prefix K, value V, ok bool) {
+	n := &t.root
+	for i := 0; ; i++ {
+		if n.isEnd {
+			prefix, value, ok = key[:i], n.value, true
+		}
+		if i == len(key) {
+			return
+		}
+		if n, _ = n.child(key[i]); n == nil {
+			return
+		}
+	}
+}
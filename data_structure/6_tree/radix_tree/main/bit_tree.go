@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"math/bits"
+)
+
+// 基数树（radix tree）在字典树的基础上做路径压缩：只有一个子节点、本身又不是键的节点被合并掉，
+// 每条边上是一段比特串而不是单个字符，节点数不超过键数的两倍，与键的长度无关。
+// Patricia 树是按比特分叉的基数树：每个节点最多两个子节点，由键在分叉处的那一位选择。
+//
+// 这里的键是任意长度的比特串，字节串是长度为 8 的倍数的特例，IP 前缀是长度为前缀长度的比特串。
+// 每个节点保存从根到它的完整比特串，查找时只需比较节点的键与要查的键的公共前缀长度。
+
+type bitNode[V any] struct {
+	key      []byte // 从根到这个节点的比特串，最后一个字节中超出 bits 的位为 0
+	bits     int
+	value    V
+	hasValue bool
+	child    [2]*bitNode[V] // 按第 bits 位选择
+}
+
+// BitTree 以比特串为键的 Patricia 树，零值是一棵空树
+type BitTree[V any] struct {
+	root *bitNode[V]
+	size int
+}
+
+// 第 i 位，最高位在前
+func bitAt(key []byte, i int) int {
+	return int(key[i/8]>>(7-i%8)) & 1
+}
+
+// 两个比特串前 n 位中公共前缀的长度
+func commonBits(a, b []byte, n int) int {
+	for i := 0; i*8 < n; i++ {
+		if x := a[i] ^ b[i]; x != 0 {
+			return min(n, i*8+bits.LeadingZeros8(x))
+		}
+	}
+	return n
+}
+
+// 比特串的前 n 位，复制一份并把其余的位清零
+func truncate(key []byte, n int) []byte {
+	k := bytes.Clone(key[:(n+7)/8])
+	if n%8 != 0 {
+		k[len(k)-1] &= 0xff << (8 - n%8)
+	}
+	return k
+}
+
+// prefixOf 判断节点的键是否是 key 前 n 位的前缀
+func (nd *bitNode[V]) prefixOf(key []byte, n int) bool {
+	return nd.bits <= n && commonBits(nd.key, key, nd.bits) == nd.bits
+}
+
+// Insert 插入键为 key 前 n 位的值，键已经存在时替换值并返回 false
+func (t *BitTree[V]) Insert(key []byte, n int, value V) bool {
+	key = truncate(key, n)
+	leaf := &bitNode[V]{key: key, bits: n, value: value, hasValue: true}
+	p := &t.root
+	for {
+		nd := *p
+		if nd == nil {
+			*p = leaf
+			t.size++
+			return true
+		}
+		c := commonBits(nd.key, key, min(nd.bits, n))
+		switch {
+		case c == nd.bits && c == n:
+			// 已有这个节点，可能是之前的分叉节点
+			added := !nd.hasValue
+			nd.value, nd.hasValue = value, true
+			if added {
+				t.size++
+			}
+			return added
+		case c == nd.bits:
+			// 节点的键是新键的前缀，继续往下走
+			p = &nd.child[bitAt(key, c)]
+		case c == n:
+			// 新键是节点的键的前缀，新节点插在它上面
+			leaf.child[bitAt(nd.key, c)] = nd
+			*p = leaf
+			t.size++
+			return true
+		default:
+			// 在第 c 位分叉，增加一个没有值的分叉节点
+			fork := &bitNode[V]{key: truncate(key, c), bits: c}
+			fork.child[bitAt(nd.key, c)] = nd
+			fork.child[bitAt(key, c)] = leaf
+			*p = fork
+			t.size++
+			return true
+		}
+	}
+}
+
+// 键恰好为 key 前 n 位的节点
+func (t *BitTree[V]) find(key []byte, n int) *bitNode[V] {
+	for nd := t.root; nd != nil && nd.prefixOf(key, n); nd = nd.child[bitAt(key, nd.bits)] {
+		if nd.bits == n {
+			return nd
+		}
+	}
+	return nil
+}
+
+// Get 返回键为 key 前 n 位的值
+func (t *BitTree[V]) Get(key []byte, n int) (V, bool) {
+	nd := t.find(key, n)
+	if nd == nil || !nd.hasValue {
+		var zero V
+		return zero, false
+	}
+	return nd.value, true
+}
+
+// Delete 删除键为 key 前 n 位的值，键不存在时返回 false
+func (t *BitTree[V]) Delete(key []byte, n int) bool {
+	var parent **bitNode[V] // 指向 nd 的父节点的指针
+	p := &t.root
+	for *p != nil && (*p).prefixOf(key, n) && (*p).bits < n {
+		parent = p
+		p = &(*p).child[bitAt(key, (*p).bits)]
+	}
+	nd := *p
+	if nd == nil || nd.bits != n || !nd.prefixOf(key, n) || !nd.hasValue {
+		return false
+	}
+	t.size--
+	var zero V
+	nd.value, nd.hasValue = zero, false
+	switch {
+	case nd.child[0] != nil && nd.child[1] != nil:
+		// 仍然是分叉节点
+		return true
+	case nd.child[0] != nil:
+		*p = nd.child[0]
+		return true
+	case nd.child[1] != nil:
+		*p = nd.child[1]
+		return true
+	}
+	*p = nil
+	// 父节点如果是没有值的分叉节点，现在只剩一个子节点，也要合并掉
+	if parent != nil && !(*parent).hasValue {
+		pn := *parent
+		if pn.child[0] != nil {
+			*parent = pn.child[0]
+		} else {
+			*parent = pn.child[1]
+		}
+	}
+	return true
+}
+
+// Len 返回键的个数
+func (t *BitTree[V]) Len() int {
+	return t.size
+}
+
+// LongestPrefix 返回树中是 key 前 n 位的前缀的最长的键的长度和值
+func (t *BitTree[V]) LongestPrefix(key []byte, n int) (bits int, value V, ok bool) {
+	for nd := t.root; nd != nil && nd.prefixOf(key, n); {
+		if nd.hasValue {
+			bits, value, ok = nd.bits, nd.value, true
+		}
+		if nd.bits == n {
+			break
+		}
+		nd = nd.child[bitAt(key, nd.bits)]
+	}
+	return bits, value, ok
+}
+
+// WalkCovering 从短到长遍历所有是 key 前 n 位的前缀的键（包括它本身），fn 返回 false 时停止
+func (t *BitTree[V]) WalkCovering(key []byte, n int, fn func(key []byte, bits int, value V) bool) {
+	for nd := t.root; nd != nil && nd.prefixOf(key, n); {
+		if nd.hasValue && !fn(nd.key, nd.bits, nd.value) {
+			return
+		}
+		if nd.bits == n {
+			return
+		}
+		nd = nd.child[bitAt(key, nd.bits)]
+	}
+}
+
+// WalkPrefix 按字典序遍历所有以 key 前 n 位开头的键（包括它本身），fn 返回 false 时停止。
+// 传给 fn 的 key 属于树，不能修改
+func (t *BitTree[V]) WalkPrefix(key []byte, n int, fn func(key []byte, bits int, value V) bool) {
+	nd := t.root
+	// 找到第一个键至少有 n 位的节点，它的子树就是所有以 key 开头的键
+	for nd != nil && nd.bits < n {
+		if !nd.prefixOf(key, n) {
+			return
+		}
+		nd = nd.child[bitAt(key, nd.bits)]
+	}
+	if nd != nil && commonBits(nd.key, key, n) == n {
+		walkBits(nd, fn)
+	}
+}
+
+// 先序遍历：节点本身排在子树之前，0 分支排在 1 分支之前，就是比特串的字典序
+func walkBits[V any](nd *bitNode[V], fn func(key []byte, bits int, value V) bool) bool {
+	if nd == nil {
+		return true
+	}
+	if nd.hasValue && !fn(nd.key, nd.bits, nd.value) {
+		return false
+	}
+	return walkBits(nd.child[0], fn) && walkBits(nd.child[1], fn)
+}
+
+// nodes 返回节点总数，包括没有值的分叉节点
+func (t *BitTree[V]) nodes() int {
+	var count func(nd *bitNode[V]) int
+	count = func(nd *bitNode[V]) int {
+		if nd == nil {
+			return 0
+		}
+		return 1 + count(nd.child[0]) + count(nd.child[1])
+	}
+	return count(t.root)
+}
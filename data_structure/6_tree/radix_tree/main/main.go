@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// 节点的访问控制列表：最长前缀匹配的规则生效，没有匹配的规则时拒绝
+type ACL struct {
+	rules *PrefixTree[bool] // true 表示允许
+}
+
+func NewACL(allow, deny []string) (*ACL, error) {
+	acl := &ACL{rules: NewPrefixTree[bool]()}
+	for _, list := range []struct {
+		prefixes []string
+		allow    bool
+	}{{allow, true}, {deny, false}} {
+		for _, s := range list.prefixes {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, err
+			}
+			if err := acl.rules.Insert(p, list.allow); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return acl, nil
+}
+
+func (acl *ACL) Allowed(addr netip.Addr) (bool, netip.Prefix) {
+	p, allow, ok := acl.rules.Lookup(addr)
+	return ok && allow, p
+}
+
+// 随机的 IPv4 前缀，集中在 10.0.0.0/14 中，使前缀之间经常互相包含
+func randPrefix(rng *rand.Rand) netip.Prefix {
+	a := [4]byte{10, byte(rng.Intn(4)), byte(rng.Intn(256)), byte(rng.Intn(256))}
+	return netip.PrefixFrom(netip.AddrFrom4(a), 8+rng.Intn(25)).Masked()
+}
+
+// 随机插入和删除前缀，与逐个比较的线性列表对比查询结果
+func checkPrefixes() error {
+	rng := rand.New(rand.NewSource(1))
+	t := NewPrefixTree[int]()
+	want := make(map[netip.Prefix]int)
+	for i := 0; i < 20000; i++ {
+		p := randPrefix(rng)
+		if rng.Intn(3) == 0 {
+			_, had := want[p]
+			delete(want, p)
+			if t.Delete(p) != had {
+				return fmt.Errorf("Delete(%v) disagrees with map", p)
+			}
+		} else {
+			want[p] = i
+			t.Insert(p, i)
+		}
+
+		q := randPrefix(rng)
+		addr := q.Addr()
+		var best netip.Prefix
+		var covering, covered []netip.Prefix
+		for p := range want {
+			if p.Contains(addr) && (!best.IsValid() || p.Bits() > best.Bits()) {
+				best = p
+			}
+			if p.Bits() <= q.Bits() && p.Contains(q.Addr()) {
+				covering = append(covering, p)
+			}
+			if q.Bits() <= p.Bits() && q.Contains(p.Addr()) {
+				covered = append(covered, p)
+			}
+		}
+		if p, v, ok := t.Lookup(addr); p != best || ok && want[p] != v {
+			return fmt.Errorf("Lookup(%v) = %v, want %v", addr, p, best)
+		}
+		slices.SortFunc(covering, func(a, b netip.Prefix) int { return a.Bits() - b.Bits() })
+		slices.SortFunc(covered, func(a, b netip.Prefix) int {
+			if c := a.Addr().Compare(b.Addr()); c != 0 {
+				return c
+			}
+			return a.Bits() - b.Bits()
+		})
+		var got []netip.Prefix
+		t.Covering(q, func(p netip.Prefix, _ int) bool { got = append(got, p); return true })
+		if !slices.Equal(got, covering) {
+			return fmt.Errorf("Covering(%v) = %v, want %v", q, got, covering)
+		}
+		got = got[:0]
+		t.Covered(q, func(p netip.Prefix, _ int) bool { got = append(got, p); return true })
+		if !slices.Equal(got, covered) {
+			return fmt.Errorf("Covered(%v) = %v, want %v", q, got, covered)
+		}
+	}
+	if t.Len() != len(want) {
+		return fmt.Errorf("Len = %d, want %d", t.Len(), len(want))
+	}
+	// 节点数不超过前缀数的两倍；全部删除后树为空
+	if n := t.v4.nodes(); n > 2*len(want) {
+		return fmt.Errorf("%d nodes for %d prefixes", n, len(want))
+	}
+	for p := range want {
+		t.Delete(p)
+	}
+	if t.v4.root != nil {
+		return fmt.Errorf("nodes left after deleting every prefix")
+	}
+	return nil
+}
+
+// 字节串键与 map 对比
+func checkStrings() error {
+	rng := rand.New(rand.NewSource(2))
+	t := NewTree[int]()
+	want := make(map[string]int)
+	randKey := func() string {
+		b := make([]byte, rng.Intn(5))
+		for i := range b {
+			b[i] = "ab\x00\xff"[rng.Intn(4)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 20000; i++ {
+		k := randKey()
+		if rng.Intn(3) == 0 {
+			_, had := want[k]
+			delete(want, k)
+			if t.Delete([]byte(k)) != had {
+				return fmt.Errorf("Delete(%q) disagrees with map", k)
+			}
+		} else {
+			_, had := want[k]
+			want[k] = i
+			if t.Insert(k, i) == had {
+				return fmt.Errorf("Insert(%q) disagrees with map", k)
+			}
+		}
+		p := randKey()
+		var keys []string
+		longest := -1
+		for k := range want {
+			if strings.HasPrefix(k, p) {
+				keys = append(keys, k)
+			}
+			if strings.HasPrefix(p, k) {
+				longest = max(longest, len(k))
+			}
+		}
+		slices.Sort(keys)
+		var walked []string
+		t.WalkPrefix(p, func(key string, value int) bool {
+			walked = append(walked, key)
+			return want[key] == value
+		})
+		if !slices.Equal(keys, walked) || t.HasPrefix(p) != (len(keys) > 0) {
+			return fmt.Errorf("prefix %q: walked %q, want %q", p, walked, keys)
+		}
+		if lp, v, ok := t.LongestPrefix(p); ok != (longest >= 0) || ok && (len(lp) != longest || want[lp] != v) {
+			return fmt.Errorf("LongestPrefix(%q) = %q", p, lp)
+		}
+		if v, ok := t.Get(p); ok != (want[p] == v && keyIn(want, p)) {
+			return fmt.Errorf("Get(%q) disagrees with map", p)
+		}
+	}
+	return nil
+}
+
+func keyIn(m map[string]int, k string) bool {
+	_, ok := m[k]
+	return ok
+}
+
+func main() {
+	acl, err := NewACL(
+		[]string{"10.0.0.0/8", "192.168.1.0/24", "2001:db8::/32"},
+		[]string{"10.66.0.0/16", "10.66.6.0/24", "2001:db8:bad::/48", "::ffff:10.99.0.0/112"},
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	// 在 10.66.0.0/16 里重新允许一台主机
+	acl.rules.Insert(netip.MustParsePrefix("10.66.6.6/32"), true)
+	// IPv4 映射的规则 ::ffff:10.99.0.0/112 按 10.99.0.0/16 生效
+	for _, s := range []string{"10.1.2.3", "10.66.1.1", "10.66.6.7", "10.66.6.6", "192.168.2.1", "::ffff:192.168.1.9",
+		"10.99.1.1", "::ffff:10.99.1.1", "2001:db8::1", "2001:db8:bad::1"} {
+		ok, rule := acl.Allowed(netip.MustParseAddr(s))
+		fmt.Printf("%-20s %-5v %v\n", s, ok, rule)
+	}
+
+	// 上级网段和下级网段
+	fmt.Print("包含 10.66.6.6/32 的规则：")
+	acl.rules.Covering(netip.MustParsePrefix("10.66.6.6/32"), func(p netip.Prefix, allow bool) bool {
+		fmt.Print(p, "=", allow, " ")
+		return true
+	})
+	fmt.Print("\n10.0.0.0/8 中的规则：")
+	acl.rules.Covered(netip.MustParsePrefix("10.0.0.0/8"), func(p netip.Prefix, allow bool) bool {
+		fmt.Print(p, "=", allow, " ")
+		return true
+	})
+	fmt.Println()
+
+	// 路径压缩：节点数与键的长度无关
+	words := NewTree[int]()
+	for i, w := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"} {
+		words.Insert(w, i)
+	}
+	fmt.Println("7 个单词共", words.bits.nodes(), "个节点") // 未压缩的字典树有 27 个节点（不含根节点）
+	words.WalkPrefix("rub", func(key string, _ int) bool {
+		fmt.Print(key, " ")
+		return true
+	})
+	fmt.Println()
+
+	for _, check := range []func() error{checkPrefixes, checkStrings} {
+		if err := check(); err != nil {
+			fmt.Println("检查失败：", err)
+			return
+		}
+	}
+	fmt.Println("随机检查通过")
+}
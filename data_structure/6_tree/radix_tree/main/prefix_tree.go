@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"net/netip"
+)
+
+// PrefixTree 以 IP 前缀为键的 Patricia 树，用于节点的允许/拒绝列表等按地址匹配规则的场景。
+// 前缀 a.b.c.d/n 就是地址的前 n 位，IPv4 和 IPv6 分别放在两棵树中。
+// IPv4 映射的 IPv6 前缀 ::ffff:a.b.c.d/n 与 IPv4 前缀 a.b.c.d/(n-96) 是同一段地址，统一按后者存放和查询。
+//   - Lookup：最长前缀匹配，找出包含某个地址的最具体的规则；
+//   - Covering：包含某个前缀的所有前缀（它本身和它的上级网段）；
+//   - Covered：被某个前缀包含的所有前缀（它本身和它的下级网段）。
+
+var errInvalidPrefix = errors.New("invalid prefix")
+
+type PrefixTree[V any] struct {
+	v4, v6 BitTree[V]
+}
+
+func NewPrefixTree[V any]() *PrefixTree[V] {
+	return &PrefixTree[V]{}
+}
+
+func (t *PrefixTree[V]) tree(addr netip.Addr) *BitTree[V] {
+	if addr.Is4() {
+		return &t.v4
+	}
+	return &t.v6
+}
+
+// 清零主机位，IPv4 映射的前缀转换为 IPv4 前缀
+func normalize(p netip.Prefix) netip.Prefix {
+	p = p.Masked()
+	// 前缀长度不足 96 时 ::ffff: 部分已被清零，不再是 IPv4 映射的地址
+	if a := p.Addr(); a.Is4In6() {
+		return netip.PrefixFrom(a.Unmap(), p.Bits()-96)
+	}
+	return p
+}
+
+// 由树中的键还原前缀
+func toPrefix(key []byte, bits int, is4 bool) netip.Prefix {
+	var a [16]byte
+	copy(a[:], key)
+	if is4 {
+		return netip.PrefixFrom(netip.AddrFrom4([4]byte(a[:4])), bits)
+	}
+	return netip.PrefixFrom(netip.AddrFrom16(a), bits)
+}
+
+// Insert 插入前缀，主机位会被清零，例如 10.1.2.3/8 按 10.0.0.0/8 插入
+func (t *PrefixTree[V]) Insert(p netip.Prefix, value V) error {
+	if !p.IsValid() {
+		return errInvalidPrefix
+	}
+	p = normalize(p)
+	t.tree(p.Addr()).Insert(p.Addr().AsSlice(), p.Bits(), value)
+	return nil
+}
+
+// Get 返回恰好为 p 的前缀的值
+func (t *PrefixTree[V]) Get(p netip.Prefix) (V, bool) {
+	if !p.IsValid() {
+		var zero V
+		return zero, false
+	}
+	p = normalize(p)
+	return t.tree(p.Addr()).Get(p.Addr().AsSlice(), p.Bits())
+}
+
+// Delete 删除前缀，不存在时返回 false
+func (t *PrefixTree[V]) Delete(p netip.Prefix) bool {
+	if !p.IsValid() {
+		return false
+	}
+	p = normalize(p)
+	return t.tree(p.Addr()).Delete(p.Addr().AsSlice(), p.Bits())
+}
+
+// Len 返回前缀的个数
+func (t *PrefixTree[V]) Len() int {
+	return t.v4.Len() + t.v6.Len()
+}
+
+// Lookup 返回包含 addr 的最长前缀及其值。IPv4 映射的 IPv6 地址（::ffff:a.b.c.d）按 IPv4 地址查找
+func (t *PrefixTree[V]) Lookup(addr netip.Addr) (netip.Prefix, V, bool) {
+	addr = addr.Unmap()
+	bits, value, ok := t.tree(addr).LongestPrefix(addr.AsSlice(), addr.BitLen())
+	if !ok {
+		return netip.Prefix{}, value, false
+	}
+	return netip.PrefixFrom(addr, bits).Masked(), value, true
+}
+
+// Covering 从短到长遍历包含 p 的所有前缀（包括 p 本身），fn 返回 false 时停止
+func (t *PrefixTree[V]) Covering(p netip.Prefix, fn func(p netip.Prefix, value V) bool) {
+	if !p.IsValid() {
+		return
+	}
+	p = normalize(p)
+	is4 := p.Addr().Is4()
+	t.tree(p.Addr()).WalkCovering(p.Addr().AsSlice(), p.Bits(), func(key []byte, bits int, value V) bool {
+		return fn(toPrefix(key, bits, is4), value)
+	})
+}
+
+// Covered 按地址顺序遍历被 p 包含的所有前缀（包括 p 本身），同一地址上短的前缀在前，fn 返回 false 时停止
+func (t *PrefixTree[V]) Covered(p netip.Prefix, fn func(p netip.Prefix, value V) bool) {
+	if !p.IsValid() {
+		return
+	}
+	p = normalize(p)
+	is4 := p.Addr().Is4()
+	t.tree(p.Addr()).WalkPrefix(p.Addr().AsSlice(), p.Bits(), func(key []byte, bits int, value V) bool {
+		return fn(toPrefix(key, bits, is4), value)
+	})
+}
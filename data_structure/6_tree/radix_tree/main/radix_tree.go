@@ -0,0 +1,61 @@
+package main
+
+// Tree 以字节串为键的基数树，零值是一棵空树。除了 CountPrefix 以外接口与 Dictionary_tree 中的 Trie 相同，
+// 节点不记录子树中的键数，统计前缀只能遍历子树，所以没有提供。
+// 字节串按比特展开后存放在 BitTree 中，遍历顺序仍然是字节的字典序
+
+// Key 键可以是字符串或字节切片
+type Key interface {
+	~string | ~[]byte
+}
+
+type Tree[V any] struct {
+	bits BitTree[V]
+}
+
+func NewTree[V any]() *Tree[V] {
+	return &Tree[V]{}
+}
+
+// Insert 插入键值对，键已经存在时替换值并返回 false
+func (t *Tree[V]) Insert[K Key](key K, value V) bool {
+	return t.bits.Insert([]byte(key), 8*len(key), value)
+}
+
+// Get 返回键对应的值
+func (t *Tree[V]) Get[K Key](key K) (V, bool) {
+	return t.bits.Get([]byte(key), 8*len(key))
+}
+
+// Delete 删除键，键不存在时返回 false
+func (t *Tree[V]) Delete[K Key](key K) bool {
+	return t.bits.Delete([]byte(key), 8*len(key))
+}
+
+// Len 返回键的个数
+func (t *Tree[V]) Len() int {
+	return t.bits.Len()
+}
+
+// HasPrefix 判断是否有以 prefix 开头的键
+func (t *Tree[V]) HasPrefix[K Key](prefix K) bool {
+	found := false
+	t.bits.WalkPrefix([]byte(prefix), 8*len(prefix), func([]byte, int, V) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// WalkPrefix 按字典序遍历以 prefix 开头的键值对，fn 返回 false 时停止
+func (t *Tree[V]) WalkPrefix[K Key](prefix K, fn func(key K, value V) bool) {
+	t.bits.WalkPrefix([]byte(prefix), 8*len(prefix), func(key []byte, _ int, value V) bool {
+		return fn(K(string(key)), value)
+	})
+}
+
+// LongestPrefix 返回树中是 key 的前缀的最长的键及其值
+func (t *Tree[V]) LongestPrefix[K Key](key K) (prefix K, value V, ok bool) {
+	n, value, ok := t.bits.LongestPrefix([]byte(key), 8*len(key))
+	return key[:n/8], value, ok
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// 布隆过滤器：m 位的位数组和 k 个哈希函数。插入时把 k 个位置 1，查询时 k 个位都为 1 才认为可能存在。
+// 可能误判存在，但不会漏判。k 个哈希函数用双重哈希 g_i = h1 + i·h2 模拟（Kirsch & Mitzenmacher），
+// 只需计算一次 128 位哈希。过滤器会序列化后发给其它节点，各节点要对同一数据算出相同的位置。
+
+var (
+	errIncompatible = errors.New("filters have different parameters")
+	errCorrupt      = errors.New("corrupt filter encoding")
+	errInvalidFPR   = errors.New("false positive rate must be in (0, 1)")
+)
+
+// 序列化格式的第一个字节，区分过滤器类型
+const (
+	kindStandard byte = iota + 1
+	kindCounting
+	kindPartitioned
+	kindScalable
+)
+
+// 哈希函数个数的上限，OptimalParams 和反序列化使用同一个上限
+const maxHashes = 64
+
+// 数据的两个基础哈希值，h2 为奇数，保证在 m 为 2 的幂时也能遍历不同位置
+func baseHashes(data []byte) (uint64, uint64) {
+	h := fnv.New128a()
+	h.Write(data)
+	var sum [16]byte
+	h.Sum(sum[:0])
+	return mix(binary.LittleEndian.Uint64(sum[:8])), mix(binary.LittleEndian.Uint64(sum[8:])) | 1
+}
+
+// splitmix64 的终结函数
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// OptimalParams 容纳 n 个元素、误判率为 p 时的最优位数 m 和哈希函数个数 k：
+// m = -n·ln p / (ln 2)²，k = m/n · ln 2，k 不超过 maxHashes（p 小于约 4e-20 时才会截断）。
+// p 不在 (0, 1) 内时返回错误
+func OptimalParams(n uint64, p float64) (m uint64, k uint32, err error) {
+	if !(p > 0 && p < 1) {
+		return 0, 0, errInvalidFPR
+	}
+	n = max(n, 1)
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint32(min(maxHashes, max(1, math.Round(float64(m)/float64(n)*math.Ln2))))
+	return max(m, 1), k, nil
+}
+
+// 位数组，标准和分区布隆过滤器共用
+type bitset []uint64
+
+func newBitset(m uint64) bitset {
+	return make(bitset, (m+63)/64)
+}
+
+func (b bitset) set(i uint64) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b bitset) get(i uint64) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b bitset) count() uint64 {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return uint64(n)
+}
+
+// 序列化的公共部分：类型、m、k、已插入元素数，然后是位数组
+func appendHeader(buf []byte, kind byte, m uint64, k uint32, n uint64) []byte {
+	buf = append(buf, kind)
+	buf = binary.AppendUvarint(buf, m)
+	buf = binary.AppendUvarint(buf, uint64(k))
+	return binary.AppendUvarint(buf, n)
+}
+
+func readHeader(data []byte, kind byte) (m uint64, k uint32, n uint64, rest []byte, err error) {
+	if len(data) == 0 || data[0] != kind {
+		return 0, 0, 0, nil, errCorrupt
+	}
+	data = data[1:]
+	var fields [3]uint64
+	for i := range fields {
+		v, size := binary.Uvarint(data)
+		if size <= 0 {
+			return 0, 0, 0, nil, errCorrupt
+		}
+		fields[i], data = v, data[size:]
+	}
+	if fields[0] == 0 || fields[1] == 0 || fields[1] > maxHashes {
+		return 0, 0, 0, nil, errCorrupt
+	}
+	return fields[0], uint32(fields[1]), fields[2], data, nil
+}
+
+func appendBits(buf []byte, b bitset) []byte {
+	for _, w := range b {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	return buf
+}
+
+func readBits(data []byte, m uint64) (bitset, error) {
+	// 先检查长度再分配，损坏的 m 不能导致巨大的分配。m 接近 2^64 时 (m+63)/64 会溢出，分开计算
+	words := m/64 + min(m%64, 1)
+	if uint64(len(data))%8 != 0 || uint64(len(data))/8 != words {
+		return nil, errCorrupt
+	}
+	b := make(bitset, words)
+	for i := range b {
+		b[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+	return b, nil
+}
+
+// BloomFilter 标准布隆过滤器
+type BloomFilter struct {
+	m    uint64
+	k    uint32
+	n    uint64 // 插入次数，合并后为估计值
+	bits bitset
+}
+
+// NewBloomFilter 创建能容纳 n 个元素、误判率约为 p 的布隆过滤器
+func NewBloomFilter(n uint64, p float64) (*BloomFilter, error) {
+	m, k, err := OptimalParams(n, p)
+	if err != nil {
+		return nil, err
+	}
+	return &BloomFilter{m: m, k: k, bits: newBitset(m)}, nil
+}
+
+func (f *BloomFilter) Add(data []byte) {
+	h1, h2 := baseHashes(data)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		f.bits.set((h1 + i*h2) % f.m)
+	}
+	f.n++
+}
+
+// Test 判断数据是否可能已经插入；返回 false 时一定没有插入
+func (f *BloomFilter) Test(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		if !f.bits.get((h1 + i*h2) % f.m) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len 返回插入次数
+func (f *BloomFilter) Len() uint64 {
+	return f.n
+}
+
+// EstimatedFPR 根据置 1 的位的比例估计当前误判率
+func (f *BloomFilter) EstimatedFPR() float64 {
+	return math.Pow(float64(f.bits.count())/float64(f.m), float64(f.k))
+}
+
+// Clone 复制一份过滤器
+func (f *BloomFilter) Clone() *BloomFilter {
+	c := *f
+	c.bits = append(bitset(nil), f.bits...)
+	return &c
+}
+
+// Union 把 other 合并进来，之后 f 包含两个集合的并集。两者的 m、k 必须相同
+func (f *BloomFilter) Union(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return errIncompatible
+	}
+	for i := range f.bits {
+		f.bits[i] |= other.bits[i]
+	}
+	f.n += other.n
+	return nil
+}
+
+// Intersect 与 other 求交，结果的误判率可能高于直接用交集构造的过滤器
+func (f *BloomFilter) Intersect(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return errIncompatible
+	}
+	for i := range f.bits {
+		f.bits[i] &= other.bits[i]
+	}
+	f.n = min(f.n, other.n)
+	return nil
+}
+
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	buf := appendHeader(nil, kindStandard, f.m, f.k, f.n)
+	return appendBits(buf, f.bits), nil
+}
+
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	m, k, n, rest, err := readHeader(data, kindStandard)
+	if err != nil {
+		return err
+	}
+	b, err := readBits(rest, m)
+	if err != nil {
+		return err
+	}
+	*f = BloomFilter{m: m, k: k, n: n, bits: b}
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding"
+	"fmt"
+	"strconv"
+)
+
+// Filter 各种布隆过滤器共同的接口
+type Filter interface {
+	Add(data []byte)
+	Test(data []byte) bool
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+const (
+	n = 100000
+	p = 0.01
+)
+
+func key(i int) []byte {
+	return []byte("account-" + strconv.Itoa(i))
+}
+
+// 插入 0..n-1，检查没有漏判，并用 n..2n-1 测量误判率
+func measure(f Filter) (fpr float64, err error) {
+	for i := 0; i < n; i++ {
+		f.Add(key(i))
+	}
+	for i := 0; i < n; i++ {
+		if !f.Test(key(i)) {
+			return 0, fmt.Errorf("false negative for %s", key(i))
+		}
+	}
+	fp := 0
+	for i := n; i < 2*n; i++ {
+		if f.Test(key(i)) {
+			fp++
+		}
+	}
+	return float64(fp) / n, nil
+}
+
+// 序列化再反序列化后，查询结果完全相同
+func roundTrip(f, g Filter) error {
+	data, err := f.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := g.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	for i := 0; i < 2*n; i++ {
+		if f.Test(key(i)) != g.Test(key(i)) {
+			return fmt.Errorf("decoded filter differs at %s", key(i))
+		}
+	}
+	return nil
+}
+
+func main() {
+	filters := []struct {
+		name string
+		new  func() Filter
+	}{
+		{"标准", func() Filter { return must(NewBloomFilter(n, p)) }},
+		{"计数", func() Filter { return must(NewCountingBloomFilter(n, p)) }},
+		{"分区", func() Filter { return must(NewPartitionedBloomFilter(n, p)) }},
+		{"可扩展", func() Filter { return must(NewScalableBloomFilter(1000, p)) }},
+	}
+	for _, kind := range filters {
+		f := kind.new()
+		fpr, err := measure(f)
+		if err != nil {
+			fmt.Println(kind.name, err)
+			return
+		}
+		if err := roundTrip(f, kind.new()); err != nil {
+			fmt.Println(kind.name, err)
+			return
+		}
+		data, _ := f.MarshalBinary()
+		fmt.Printf("%s：误判率 %.3f%%（目标 %.1f%%），序列化 %d 字节，每个元素 %.1f 位\n",
+			kind.name, fpr*100, p*100, len(data), float64(len(data))*8/n)
+	}
+
+	// 计数布隆过滤器的删除
+	c := must(NewCountingBloomFilter(n, p))
+	for i := 0; i < n; i++ {
+		c.Add(key(i))
+	}
+	for i := 0; i < n; i += 2 {
+		c.Remove(key(i))
+	}
+	kept, gone := 0, 0
+	for i := 0; i < n; i++ {
+		if c.Test(key(i)) {
+			if i%2 == 1 {
+				kept++
+			} else {
+				gone++ // 已删除但仍被判为存在：误判
+			}
+		}
+	}
+	fmt.Printf("删除一半后：保留 %d/%d，已删除的误判 %d，Len %d\n", kept, n/2, gone, c.Len())
+	fmt.Println(c.Remove([]byte("never added")))
+
+	// 可扩展布隆过滤器按需追加层
+	s := must(NewScalableBloomFilter(1000, p))
+	for i := 0; i < n; i++ {
+		s.Add(key(i))
+	}
+	// 误判为已存在的元素不会插入，所以 Len 略小于 n
+	fmt.Println("可扩展布隆过滤器：", s.Len(), "个元素，", s.Layers(), "层")
+
+	// 并集与交集：a 含 0..n-1，b 含 n/2..3n/2-1
+	a, b := must(NewBloomFilter(2*n, p)), must(NewBloomFilter(2*n, p))
+	for i := 0; i < n; i++ {
+		a.Add(key(i))
+		b.Add(key(i + n/2))
+	}
+	union, inter := a.Clone(), a.Clone()
+	union.Union(b)
+	inter.Intersect(b)
+	inUnion, inInter, interFP := 0, 0, 0
+	for i := 0; i < 2*n; i++ {
+		if union.Test(key(i)) {
+			inUnion++
+		}
+		if inter.Test(key(i)) {
+			if i >= n/2 && i < n {
+				inInter++
+			} else {
+				interFP++
+			}
+		}
+	}
+	fmt.Printf("并集命中 %d（实际 %d），交集命中 %d/%d，交集误判 %d\n", inUnion, 3*n/2, inInter, n/2, interFP)
+	fmt.Println(a.Union(must(NewBloomFilter(n, p))))
+	fmt.Println(s.Union(must(NewScalableBloomFilter(1000, p/2))))
+	fmt.Println(s.Intersect(must(NewScalableBloomFilter(1000, p))))
+
+	// 误判率极小时 k 被截断为 maxHashes，序列化后仍能读回
+	for _, kind := range []struct {
+		name string
+		f, g Filter
+	}{
+		{"标准", must(NewBloomFilter(1000, 1e-25)), new(BloomFilter)},
+		{"计数", must(NewCountingBloomFilter(1000, 1e-25)), new(CountingBloomFilter)},
+		{"分区", must(NewPartitionedBloomFilter(1000, 1e-25)), new(PartitionedBloomFilter)},
+		{"可扩展", must(NewScalableBloomFilter(100, 1e-25)), new(ScalableBloomFilter)},
+	} {
+		for i := 0; i < 1000; i++ {
+			kind.f.Add(key(i))
+		}
+		fmt.Println("p=1e-25", kind.name, roundTrip(kind.f, kind.g))
+	}
+
+	// 参数和编码的检查
+	_, err := NewBloomFilter(n, 0)
+	fmt.Println(err)
+	corrupt := appendHeader(nil, kindStandard, 1<<62, 7, 0)
+	fmt.Println(new(BloomFilter).UnmarshalBinary(corrupt))
+}
+
+// 示例中的参数都是合法的，出错说明代码有问题
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
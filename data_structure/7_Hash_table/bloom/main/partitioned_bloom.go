@@ -0,0 +1,92 @@
+package main
+
+// 分区布隆过滤器：把 m 位平均分成 k 个分区，第 i 个哈希函数只在第 i 个分区中置位。
+// 每个元素在每个分区恰好置一位，各哈希函数不会互相覆盖，误判率与标准布隆过滤器几乎相同，
+// 但更容易分析，也便于把各分区放到不同的内存块或并行查询。
+
+type PartitionedBloomFilter struct {
+	m    uint64 // 总位数，为 k 的整数倍
+	k    uint32
+	n    uint64
+	bits bitset
+}
+
+func NewPartitionedBloomFilter(n uint64, p float64) (*PartitionedBloomFilter, error) {
+	m, k, err := OptimalParams(n, p)
+	if err != nil {
+		return nil, err
+	}
+	m = (m + uint64(k) - 1) / uint64(k) * uint64(k)
+	return &PartitionedBloomFilter{m: m, k: k, bits: newBitset(m)}, nil
+}
+
+func (f *PartitionedBloomFilter) partSize() uint64 {
+	return f.m / uint64(f.k)
+}
+
+func (f *PartitionedBloomFilter) Add(data []byte) {
+	h1, h2 := baseHashes(data)
+	s := f.partSize()
+	for i := uint64(0); i < uint64(f.k); i++ {
+		f.bits.set(i*s + (h1+i*h2)%s)
+	}
+	f.n++
+}
+
+func (f *PartitionedBloomFilter) Test(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	s := f.partSize()
+	for i := uint64(0); i < uint64(f.k); i++ {
+		if !f.bits.get(i*s + (h1+i*h2)%s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *PartitionedBloomFilter) Len() uint64 {
+	return f.n
+}
+
+func (f *PartitionedBloomFilter) Union(other *PartitionedBloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return errIncompatible
+	}
+	for i := range f.bits {
+		f.bits[i] |= other.bits[i]
+	}
+	f.n += other.n
+	return nil
+}
+
+func (f *PartitionedBloomFilter) Intersect(other *PartitionedBloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return errIncompatible
+	}
+	for i := range f.bits {
+		f.bits[i] &= other.bits[i]
+	}
+	f.n = min(f.n, other.n)
+	return nil
+}
+
+func (f *PartitionedBloomFilter) MarshalBinary() ([]byte, error) {
+	buf := appendHeader(nil, kindPartitioned, f.m, f.k, f.n)
+	return appendBits(buf, f.bits), nil
+}
+
+func (f *PartitionedBloomFilter) UnmarshalBinary(data []byte) error {
+	m, k, n, rest, err := readHeader(data, kindPartitioned)
+	if err != nil {
+		return err
+	}
+	if m%uint64(k) != 0 {
+		return errCorrupt
+	}
+	b, err := readBits(rest, m)
+	if err != nil {
+		return err
+	}
+	*f = PartitionedBloomFilter{m: m, k: k, n: n, bits: b}
+	return nil
+}
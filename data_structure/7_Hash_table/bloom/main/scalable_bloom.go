@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// 可扩展布隆过滤器（Almeida 等, 2007）：事先不知道元素个数时使用。
+// 由一串标准布隆过滤器组成，当前的过滤器装满后追加一个容量为 growth 倍的新过滤器，
+// 第 i 个过滤器的误判率为 p·(1-r)·r^i，各层误判率之和不超过 p。查询时任意一层命中即为命中。
+
+const (
+	scalableGrowth     = 2
+	scalableTightening = 0.8 // r
+)
+
+var errScalableIntersect = errors.New("scalable Bloom filters cannot be intersected: an element may sit in different layers of the two filters")
+
+type ScalableBloomFilter struct {
+	initial uint64  // 第一层的容量
+	p       float64 // 总误判率
+	layers  []*BloomFilter
+	caps    []uint64 // 每层的容量
+}
+
+func NewScalableBloomFilter(initial uint64, p float64) (*ScalableBloomFilter, error) {
+	if !(p > 0 && p < 1) {
+		return nil, errInvalidFPR
+	}
+	return &ScalableBloomFilter{initial: max(initial, 1), p: p}, nil
+}
+
+func (f *ScalableBloomFilter) addLayer() {
+	i := len(f.layers)
+	capacity := f.initial * uint64(math.Pow(scalableGrowth, float64(i)))
+	p := f.p * (1 - scalableTightening) * math.Pow(scalableTightening, float64(i))
+	l, _ := NewBloomFilter(capacity, p) // f.p 已经检查过，各层的 p 都在 (0, 1) 内
+	f.layers = append(f.layers, l)
+	f.caps = append(f.caps, capacity)
+}
+
+// Add 插入数据，已经（可能）存在的数据不再插入，以免重复计入容量
+func (f *ScalableBloomFilter) Add(data []byte) {
+	if f.Test(data) {
+		return
+	}
+	if n := len(f.layers); n == 0 || f.layers[n-1].Len() >= f.caps[n-1] {
+		f.addLayer()
+	}
+	f.layers[len(f.layers)-1].Add(data)
+}
+
+func (f *ScalableBloomFilter) Test(data []byte) bool {
+	for _, l := range f.layers {
+		if l.Test(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len 返回插入的元素个数
+func (f *ScalableBloomFilter) Len() uint64 {
+	n := uint64(0)
+	for _, l := range f.layers {
+		n += l.Len()
+	}
+	return n
+}
+
+// Layers 返回层数
+func (f *ScalableBloomFilter) Layers() int {
+	return len(f.layers)
+}
+
+// Union 把 other 的各层追加进来，两者的初始容量和误判率必须相同。
+// 每一层仍不超过自己的误判率，合并后的总误判率不超过 2p
+func (f *ScalableBloomFilter) Union(other *ScalableBloomFilter) error {
+	if f.initial != other.initial || f.p != other.p {
+		return errIncompatible
+	}
+	for i, l := range other.layers {
+		f.layers = append(f.layers, l.Clone())
+		f.caps = append(f.caps, other.caps[i])
+	}
+	return nil
+}
+
+// Intersect 不支持：同一个元素在两个过滤器中可能位于不同的层，逐层求交会产生漏判
+func (f *ScalableBloomFilter) Intersect(other *ScalableBloomFilter) error {
+	return errScalableIntersect
+}
+
+func (f *ScalableBloomFilter) MarshalBinary() ([]byte, error) {
+	buf := []byte{kindScalable}
+	buf = binary.AppendUvarint(buf, f.initial)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(f.p))
+	buf = binary.AppendUvarint(buf, uint64(len(f.layers)))
+	for i, l := range f.layers {
+		data, _ := l.MarshalBinary()
+		buf = binary.AppendUvarint(buf, f.caps[i])
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+func (f *ScalableBloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != kindScalable {
+		return errCorrupt
+	}
+	data = data[1:]
+	uvarint := func() (uint64, bool) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, false
+		}
+		data = data[n:]
+		return v, true
+	}
+	initial, ok := uvarint()
+	if !ok || len(data) < 8 {
+		return errCorrupt
+	}
+	g := ScalableBloomFilter{initial: initial, p: math.Float64frombits(binary.LittleEndian.Uint64(data))}
+	if initial == 0 || !(g.p > 0 && g.p < 1) {
+		return errCorrupt
+	}
+	data = data[8:]
+	layers, ok := uvarint()
+	if !ok {
+		return errCorrupt
+	}
+	for i := uint64(0); i < layers; i++ {
+		capacity, ok1 := uvarint()
+		size, ok2 := uvarint()
+		if !ok1 || !ok2 || uint64(len(data)) < size {
+			return errCorrupt
+		}
+		l := &BloomFilter{}
+		if err := l.UnmarshalBinary(data[:size]); err != nil {
+			return err
+		}
+		data = data[size:]
+		g.layers = append(g.layers, l)
+		g.caps = append(g.caps, capacity)
+	}
+	if len(data) != 0 {
+		return errCorrupt
+	}
+	*f = g
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"math"
+)
+
+// 有界负载的一致性哈希（Mirrokni, Thorup, Zadimoghaddam, 2016）：
+// 给每个成员设一个容量上限 ⌈c · (总负载+1) · 权重占比⌉，c > 1。
+// 分配键时从它在环上的位置顺时针找，跳过已满的成员，取第一个未满的成员。
+// 这样最重的成员不超过平均负载的 c 倍，同时大部分键仍然落在原本的成员上。
+
+var errNoCapacity = errors.New("all members are at capacity")
+
+type BoundedLoad struct {
+	ring  *Ring
+	c     float64
+	loads map[string]int
+	total int
+}
+
+// NewBoundedLoad 在 ring 上按负载上限系数 c 分配，c 至少为 1
+func NewBoundedLoad(ring *Ring, c float64) *BoundedLoad {
+	return &BoundedLoad{ring: ring, c: max(1, c), loads: make(map[string]int)}
+}
+
+// Remove 从环上删除成员并清除它的负载，它上面的键需要调用者重新分配。
+// 应通过它而不是直接调用 ring.Remove 来删除成员，否则负载会残留
+func (b *BoundedLoad) Remove(member string) error {
+	if err := b.ring.Remove(member); err != nil {
+		return err
+	}
+	b.total -= b.loads[member]
+	delete(b.loads, member)
+	return nil
+}
+
+// 成员当前的容量上限
+func (b *BoundedLoad) capacity(member string) int {
+	share := float64(b.ring.weights[member]) / float64(b.ring.totalWeight)
+	return int(math.Ceil(b.c * float64(b.total+1) * share))
+}
+
+// Acquire 为键分配一个成员并把该成员的负载加 1，用完后调用 Release
+func (b *BoundedLoad) Acquire(key string) (string, error) {
+	r := b.ring
+	if len(r.points) == 0 {
+		return "", errEmptyRing
+	}
+	start := r.search(hash64(key))
+	for i := 0; i < len(r.points); i++ {
+		m := r.points[(start+i)%len(r.points)].member
+		if b.loads[m]+1 <= b.capacity(m) {
+			b.loads[m]++
+			b.total++
+			return m, nil
+		}
+	}
+	return "", errNoCapacity
+}
+
+// Release 把成员的负载减 1
+func (b *BoundedLoad) Release(member string) {
+	if b.loads[member] > 0 {
+		b.loads[member]--
+		b.total--
+	}
+}
+
+// Loads 返回各成员当前的负载
+func (b *BoundedLoad) Loads() map[string]int {
+	loads := make(map[string]int, len(b.loads))
+	for m, n := range b.loads {
+		loads[m] = n
+	}
+	return loads
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const numKeys = 100000
+
+func key(i int) string {
+	return "0x" + strconv.FormatInt(int64(i)*7919, 16)
+}
+
+// 统计每个成员分到的键
+func distribution(r *Ring) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < numKeys; i++ {
+		m, _ := r.Get(key(i))
+		counts[m]++
+	}
+	return counts
+}
+
+func main() {
+	// 把索引器分片到 4 个节点，indexer-3 的机器配置是其它的两倍
+	ring := NewRing(160)
+	ring.Add("indexer-1", 1)
+	ring.Add("indexer-2", 1)
+	ring.Add("indexer-3", 2)
+	ring.Add("indexer-4", 1)
+	fmt.Println(ring.Add("indexer-1", 1))
+
+	owned := ring.Owned()
+	counts := distribution(ring)
+	for _, m := range ring.Members() {
+		fmt.Printf("%s: 哈希空间 %.2f%%，键 %d\n", m, owned[m]*100, counts[m])
+	}
+
+	replicas, _ := ring.GetN("0xdeadbeef", 3)
+	fmt.Println("副本：", replicas)
+
+	// 增加一个节点：只有约 1/6 的键迁移，而且全部迁往新节点
+	before := ring.Clone()
+	ring.Add("indexer-5", 1)
+	moves := Diff(before, ring)
+	fmt.Println(MoveReport(moves))
+	moved := 0
+	for i := 0; i < numKeys; i++ {
+		a, _ := before.Get(key(i))
+		b, _ := ring.Get(key(i))
+		if a != b {
+			moved++
+		}
+	}
+	fmt.Printf("实际迁移 %.2f%% 的键\n", float64(moved)*100/numKeys)
+
+	// 删除一个节点：它的键分散到其余节点
+	before = ring.Clone()
+	ring.Remove("indexer-2")
+	fmt.Println(MoveReport(Diff(before, ring)))
+
+	// 有界负载：最重的节点不超过按权重期望负载的 c 倍
+	plain := distribution(ring)
+	fmt.Printf("不限负载：%v，最大负载为期望的 %.3f 倍\n", plain, worstLoad(ring, plain))
+	for _, c := range []float64{1.1, 1.02} {
+		b := NewBoundedLoad(ring, c)
+		for i := 0; i < numKeys; i++ {
+			b.Acquire(key(i))
+		}
+		fmt.Printf("c=%v：%v，最大负载为期望的 %.3f 倍\n", c, b.Loads(), worstLoad(ring, b.Loads()))
+	}
+
+	// 删除成员时清除它的负载，其余成员的容量上限随之重新计算
+	b := NewBoundedLoad(ring, 1.1)
+	for i := 0; i < numKeys; i++ {
+		b.Acquire(key(i))
+	}
+	b.Remove("indexer-5")
+	fmt.Println("删除 indexer-5 后：", b.Loads())
+
+	_, err := ring.GetN("0xdeadbeef", -1)
+	fmt.Println(err)
+}
+
+// 各成员负载与按权重分配的期望负载之比的最大值
+func worstLoad(r *Ring, loads map[string]int) float64 {
+	total := 0
+	for _, n := range loads {
+		total += n
+	}
+	worst := 0.0
+	for m, n := range loads {
+		expected := float64(total) * float64(r.weights[m]) / float64(r.totalWeight)
+		worst = max(worst, float64(n)/expected)
+	}
+	return worst
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Move 哈希值在 (Start, End] 区间内的键从 From 移到 To。Start > End 表示跨过 0 的区间
+type Move struct {
+	Start, End uint64
+	From, To   string
+}
+
+// 区间占整个哈希空间的比例
+func (m Move) Fraction() float64 {
+	return float64(m.End-m.Start) / math.Exp2(64)
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("(%016x, %016x] %s -> %s", m.Start, m.End, m.From, m.To)
+}
+
+// Diff 比较成员变化前后的两个环，返回归属发生变化的所有区间。
+// 把两个环的虚拟节点位置合在一起，相邻两个位置之间的区间在两个环中的归属都是确定的，
+// 逐段比较，相邻且变化相同的区间合并
+func Diff(before, after *Ring) []Move {
+	if len(before.points) == 0 || len(after.points) == 0 {
+		return nil
+	}
+	var bounds []uint64
+	for _, p := range before.points {
+		bounds = append(bounds, p.hash)
+	}
+	for _, p := range after.points {
+		bounds = append(bounds, p.hash)
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	var moves []Move
+	prev := bounds[len(bounds)-1] // 第一段从最后一个位置绕过 0
+	for i, b := range bounds {
+		if i > 0 && b == prev {
+			continue
+		}
+		from := before.points[before.search(b)].member
+		to := after.points[after.search(b)].member
+		if from != to {
+			if n := len(moves); n > 0 && moves[n-1].End == prev && moves[n-1].From == from && moves[n-1].To == to {
+				moves[n-1].End = b
+			} else {
+				moves = append(moves, Move{prev, b, from, to})
+			}
+		}
+		prev = b
+	}
+	return moves
+}
+
+// MoveReport 按来源和去向汇总变化的区间
+func MoveReport(moves []Move) string {
+	type pair struct{ from, to string }
+	fractions := make(map[pair]float64)
+	counts := make(map[pair]int)
+	var pairs []pair
+	total := 0.0
+	for _, m := range moves {
+		p := pair{m.From, m.To}
+		if counts[p] == 0 {
+			pairs = append(pairs, p)
+		}
+		counts[p]++
+		fractions[p] += m.Fraction()
+		total += m.Fraction()
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].from != pairs[j].from {
+			return pairs[i].from < pairs[j].from
+		}
+		return pairs[i].to < pairs[j].to
+	})
+	var sb strings.Builder
+	for _, p := range pairs {
+		fmt.Fprintf(&sb, "%s -> %s: %d 个区间，%.2f%%\n", p.from, p.to, counts[p], fractions[p]*100)
+	}
+	fmt.Fprintf(&sb, "共 %d 个区间，%.2f%% 的键需要迁移", len(moves), total*100)
+	return sb.String()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// 一致性哈希环：哈希值空间 [0, 2^64) 首尾相接成环，每个成员在环上放置若干虚拟节点，
+// 键归属于从它的哈希值顺时针找到的第一个虚拟节点所属的成员。
+// 虚拟节点个数与权重成正比，权重越大分到的键越多；虚拟节点越多，分布越均匀。
+// 增删成员只影响与它的虚拟节点相邻的那些区间，其余键的归属不变。
+
+var (
+	errMemberExists = errors.New("member already in ring")
+	errNoMember     = errors.New("member not in ring")
+	errEmptyRing    = errors.New("ring is empty")
+	errNegativeN    = errors.New("replica count must not be negative")
+)
+
+// 键和虚拟节点在环上的位置：FNV-1a 再用 splitmix64 打散，各节点对同一个键算出的位置相同
+func hash64(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type point struct {
+	hash   uint64
+	member string
+}
+
+type Ring struct {
+	vnodes      int            // 每单位权重的虚拟节点数
+	weights     map[string]int // 成员及其权重
+	totalWeight int            // 所有成员的权重之和
+	points      []point        // 按哈希值排序的虚拟节点
+}
+
+// NewRing 创建一致性哈希环，权重为 1 的成员有 vnodes 个虚拟节点
+func NewRing(vnodes int) *Ring {
+	return &Ring{vnodes: max(1, vnodes), weights: make(map[string]int)}
+}
+
+// Clone 复制一份环，用于在修改成员前保存快照
+func (r *Ring) Clone() *Ring {
+	c := &Ring{vnodes: r.vnodes, weights: make(map[string]int, len(r.weights)), totalWeight: r.totalWeight}
+	for m, w := range r.weights {
+		c.weights[m] = w
+	}
+	c.points = slices.Clone(r.points)
+	return c
+}
+
+// Add 加入一个权重为 weight 的成员
+func (r *Ring) Add(member string, weight int) error {
+	if weight < 1 {
+		return fmt.Errorf("weight of %s must be positive", member)
+	}
+	if _, ok := r.weights[member]; ok {
+		return errMemberExists
+	}
+	r.weights[member] = weight
+	r.totalWeight += weight
+	for i := 0; i < weight*r.vnodes; i++ {
+		r.points = append(r.points, point{hash64(member + "#" + strconv.Itoa(i)), member})
+	}
+	// 哈希值相同时按成员名排序，保证所有节点上环的结构一致
+	sort.Slice(r.points, func(i, j int) bool {
+		if r.points[i].hash != r.points[j].hash {
+			return r.points[i].hash < r.points[j].hash
+		}
+		return r.points[i].member < r.points[j].member
+	})
+	return nil
+}
+
+// Remove 删除一个成员
+func (r *Ring) Remove(member string) error {
+	w, ok := r.weights[member]
+	if !ok {
+		return errNoMember
+	}
+	delete(r.weights, member)
+	r.totalWeight -= w
+	r.points = slices.DeleteFunc(r.points, func(p point) bool { return p.member == member })
+	return nil
+}
+
+// Members 返回所有成员，按名字排序
+func (r *Ring) Members() []string {
+	members := make([]string, 0, len(r.weights))
+	for m := range r.weights {
+		members = append(members, m)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// 哈希值 h 顺时针方向第一个虚拟节点的下标
+func (r *Ring) search(h uint64) int {
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return i
+}
+
+// Get 返回键所属的成员
+func (r *Ring) Get(key string) (string, error) {
+	if len(r.points) == 0 {
+		return "", errEmptyRing
+	}
+	return r.points[r.search(hash64(key))].member, nil
+}
+
+// GetN 返回键的 n 个副本所在的成员：从键的位置顺时针依次取不重复的成员。
+// 成员不足 n 个时返回全部成员
+func (r *Ring) GetN(key string, n int) ([]string, error) {
+	if n < 0 {
+		return nil, errNegativeN
+	}
+	if len(r.points) == 0 {
+		return nil, errEmptyRing
+	}
+	n = min(n, len(r.weights))
+	result := make([]string, 0, n)
+	for i, start := 0, r.search(hash64(key)); len(result) < n; i++ {
+		m := r.points[(start+i)%len(r.points)].member
+		if !slices.Contains(result, m) {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// Owned 返回每个成员拥有的哈希空间比例
+func (r *Ring) Owned() map[string]float64 {
+	owned := make(map[string]float64)
+	if len(r.points) == 1 {
+		owned[r.points[0].member] = 1
+		return owned
+	}
+	for i, p := range r.points {
+		prev := r.points[(i+len(r.points)-1)%len(r.points)].hash
+		owned[p.member] += float64(p.hash-prev) / math.Exp2(64) // 无符号减法自动处理环绕
+	}
+	return owned
+}
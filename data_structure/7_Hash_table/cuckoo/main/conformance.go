@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Map 哈希表的接口，与 open_addressing 中的相同。
+// 一致性检查与 ../../open_addressing/main/conformance.go 相同；布谷鸟哈希没有墓碑，
+// 但删除时会把stash 中的元素搬回桶中，删除相关的检查覆盖的正是这条路径
+type Map[K comparable, V any] interface {
+	Put(key K, value V)
+	Get(key K) (V, bool)
+	Delete(key K) bool
+	Len() int
+	Range(fn func(key K, value V) bool)
+}
+
+// conformance 所有实现都要通过的一致性检查，返回第一个不符合预期的地方
+func conformance(newMap func() Map[int, int]) error {
+	// 基本操作
+	m := newMap()
+	if _, ok := m.Get(1); ok || m.Delete(1) || m.Len() != 0 {
+		return fmt.Errorf("empty map is not empty")
+	}
+	m.Put(1, 10)
+	m.Put(1, 11)
+	if v, ok := m.Get(1); !ok || v != 11 || m.Len() != 1 {
+		return fmt.Errorf("Put did not overwrite: %v %v %v", v, ok, m.Len())
+	}
+	if !m.Delete(1) || m.Delete(1) || m.Len() != 0 {
+		return fmt.Errorf("Delete failed")
+	}
+	m.Put(0, 0) // 零值键
+	if _, ok := m.Get(0); !ok {
+		return fmt.Errorf("zero key lost")
+	}
+
+	// 扩容：连续插入后所有键都在，Range 恰好遍历每个键一次
+	m = newMap()
+	const n = 10000
+	for i := 0; i < n; i++ {
+		m.Put(i, -i)
+	}
+	seen := make(map[int]bool)
+	m.Range(func(k, v int) bool {
+		if seen[k] || v != -k {
+			panic(fmt.Sprintf("Range visited %d=%d", k, v))
+		}
+		seen[k] = true
+		return true
+	})
+	if len(seen) != n || m.Len() != n {
+		return fmt.Errorf("Range visited %d keys, Len %d, want %d", len(seen), m.Len(), n)
+	}
+	count := 0
+	m.Range(func(int, int) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		return fmt.Errorf("Range did not stop early")
+	}
+
+	// 删除一半后再插入：stash 中的元素搬回桶中时不能丢失其它键
+	for i := 0; i < n; i += 2 {
+		m.Delete(i)
+	}
+	for i := 0; i < n; i++ {
+		_, ok := m.Get(i)
+		if ok != (i%2 == 1) {
+			return fmt.Errorf("after deleting evens, Get(%d) = %v", i, ok)
+		}
+	}
+	for i := 0; i < n; i += 2 {
+		m.Put(i, i)
+	}
+	if m.Len() != n {
+		return fmt.Errorf("Len %d, want %d", m.Len(), n)
+	}
+
+	// 反复插入删除
+	m = newMap()
+	for i := 0; i < 100000; i++ {
+		m.Put(i, i)
+		m.Delete(i)
+	}
+	if m.Len() != 0 {
+		return fmt.Errorf("churn left %d keys", m.Len())
+	}
+
+	// 与内置 map 对照的随机操作，键范围较小以制造大量冲突与删除
+	want := make(map[int]int)
+	m = newMap()
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200000; i++ {
+		k := rng.Intn(3000)
+		switch rng.Intn(3) {
+		case 0:
+			m.Put(k, i)
+			want[k] = i
+		case 1:
+			_, ok := want[k]
+			if m.Delete(k) != ok {
+				return fmt.Errorf("step %d: Delete(%d) != %v", i, k, ok)
+			}
+			delete(want, k)
+		case 2:
+			v, ok := m.Get(k)
+			if w, wok := want[k]; v != w || ok != wok {
+				return fmt.Errorf("step %d: Get(%d) = %d %v, want %d %v", i, k, v, ok, w, wok)
+			}
+		}
+		if m.Len() != len(want) {
+			return fmt.Errorf("step %d: Len %d, want %d", i, m.Len(), len(want))
+		}
+	}
+	return nil
+}
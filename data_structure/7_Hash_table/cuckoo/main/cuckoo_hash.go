@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/rand"
+)
+
+// 布谷鸟哈希（cuckoo hashing）：每个键用 ways 个独立的哈希函数算出 ways 个候选桶，只能存放在这些桶中，
+// 每个桶有 slotsPerBucket 个槽。插入时候选桶都满了，就随机踢出其中一个元素，
+// 被踢出的元素再去它自己的其它候选桶，如此反复（像布谷鸟把别的鸟蛋挤出巢）。
+// 踢出次数超过 maxKicks 说明很可能陷入了循环，此时把无处安放的元素放入一个很小的溢出区（stash），
+// stash 也满了才换一组哈希种子整体重建（rehash-on-cycle）；stash 能大幅降低重建的概率。
+// 查找最多检查 ways 个桶和 stash，最坏情况也是 O(1)，适合对读延迟敏感的场景，代价是插入偶尔较慢。
+
+const (
+	slotsPerBucket = 4
+	stashSize      = 4
+	maxKicks       = 500
+	minBuckets     = 4
+)
+
+type cuckooSlot[K comparable, V any] struct {
+	key   K
+	value V
+	full  bool
+}
+
+type cuckooBucket[K comparable, V any] [slotsPerBucket]cuckooSlot[K, V]
+
+type CuckooHash[K comparable, V any] struct {
+	seeds    []maphash.Seed // 每个哈希函数一个种子
+	buckets  []cuckooBucket[K, V]
+	stash    []cuckooSlot[K, V]
+	length   int
+	rehashes int
+	rng      *rand.Rand
+}
+
+// NewCuckooHash 创建使用 ways 个哈希函数的布谷鸟哈希表，ways 至少为 2
+func NewCuckooHash[K comparable, V any](ways int) *CuckooHash[K, V] {
+	if ways < 2 {
+		panic(fmt.Sprintf("cuckoo hash needs at least 2 hash functions, got %d", ways))
+	}
+	m := &CuckooHash[K, V]{
+		seeds:   make([]maphash.Seed, ways),
+		buckets: make([]cuckooBucket[K, V], minBuckets),
+		rng:     rand.New(rand.NewSource(rand.Int63())),
+	}
+	m.reseed()
+	return m
+}
+
+func (m *CuckooHash[K, V]) reseed() {
+	for i := range m.seeds {
+		m.seeds[i] = maphash.MakeSeed()
+	}
+}
+
+func (m *CuckooHash[K, V]) Len() int {
+	return m.length
+}
+
+// 键的第 i 个候选桶
+func (m *CuckooHash[K, V]) bucket(i int, key K) *cuckooBucket[K, V] {
+	return &m.buckets[maphash.Comparable(m.seeds[i], key)&uint64(len(m.buckets)-1)]
+}
+
+func (m *CuckooHash[K, V]) find(key K) *cuckooSlot[K, V] {
+	for i := range m.seeds {
+		b := m.bucket(i, key)
+		for j := range b {
+			if b[j].full && b[j].key == key {
+				return &b[j]
+			}
+		}
+	}
+	for i := range m.stash {
+		if m.stash[i].key == key {
+			return &m.stash[i]
+		}
+	}
+	return nil
+}
+
+func (m *CuckooHash[K, V]) Get(key K) (V, bool) {
+	if s := m.find(key); s != nil {
+		return s.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *CuckooHash[K, V]) Put(key K, value V) {
+	if s := m.find(key); s != nil {
+		s.value = value
+		return
+	}
+	if (m.length+1)*10 > len(m.buckets)*slotsPerBucket*9 {
+		m.rehash(len(m.buckets)*2, nil)
+	}
+	e := cuckooSlot[K, V]{key, value, true}
+	if homeless, ok := m.place(e); !ok {
+		m.rehash(len(m.buckets), &homeless)
+	}
+	m.length++
+}
+
+// 在候选桶中找一个空槽
+func (m *CuckooHash[K, V]) placeFree(e cuckooSlot[K, V]) bool {
+	for i := range m.seeds {
+		b := m.bucket(i, e.key)
+		for j := range b {
+			if !b[j].full {
+				b[j] = e
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 放入一个元素，必要时踢出其它元素。失败时返回最终无处安放的元素（不一定是 e）
+func (m *CuckooHash[K, V]) place(e cuckooSlot[K, V]) (cuckooSlot[K, V], bool) {
+	if m.placeFree(e) {
+		return e, true
+	}
+	for kick := 0; kick < maxKicks; kick++ {
+		b := m.bucket(m.rng.Intn(len(m.seeds)), e.key)
+		j := m.rng.Intn(slotsPerBucket)
+		e, b[j] = b[j], e
+		if m.placeFree(e) {
+			return e, true
+		}
+	}
+	if len(m.stash) < stashSize {
+		m.stash = append(m.stash, e)
+		return e, true
+	}
+	return e, false
+}
+
+// 换一组哈希种子重建到 size 个桶，extra 是重建时需要额外放入的元素。
+// 同样大小连续失败几次后加倍
+func (m *CuckooHash[K, V]) rehash(size int, extra *cuckooSlot[K, V]) {
+	entries := make([]cuckooSlot[K, V], 0, m.length+1)
+	m.Range(func(k K, v V) bool {
+		entries = append(entries, cuckooSlot[K, V]{k, v, true})
+		return true
+	})
+	if extra != nil {
+		entries = append(entries, *extra)
+	}
+	for attempt := 1; ; attempt++ {
+		if extra != nil || attempt > 1 {
+			m.rehashes++
+		}
+		m.reseed()
+		m.buckets = make([]cuckooBucket[K, V], size)
+		m.stash = nil
+		ok := true
+		for _, e := range entries {
+			if _, ok = m.place(e); !ok {
+				break
+			}
+		}
+		if ok {
+			return
+		}
+		if attempt%4 == 0 {
+			size *= 2
+		}
+	}
+}
+
+func (m *CuckooHash[K, V]) Delete(key K) bool {
+	s := m.find(key)
+	if s == nil {
+		return false
+	}
+	*s = cuckooSlot[K, V]{}
+	m.length--
+	// 桶里腾出了空槽，尝试把 stash 中的元素搬回桶中
+	for i := 0; i < len(m.stash); {
+		// 被删除的元素如果在 stash 中，此时已被清空
+		if e := m.stash[i]; !e.full || m.placeFree(e) {
+			m.stash = append(m.stash[:i], m.stash[i+1:]...)
+		} else {
+			i++
+		}
+	}
+	return true
+}
+
+func (m *CuckooHash[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.buckets {
+		for _, s := range m.buckets[i] {
+			if s.full && !fn(s.key, s.value) {
+				return
+			}
+		}
+	}
+	for _, s := range m.stash {
+		if !fn(s.key, s.value) {
+			return
+		}
+	}
+}
+
+// Stats 哈希表的内部状态
+type Stats struct {
+	Len        int
+	Slots      int
+	LoadFactor float64
+	Stash      int // stash 中的元素个数
+	Rehashes   int // 因踢出循环而重建的次数，不含正常扩容
+	MaxProbes  int // 一次查找最多检查的槽数
+}
+
+func (m *CuckooHash[K, V]) Stats() Stats {
+	slots := len(m.buckets) * slotsPerBucket
+	return Stats{
+		Len:        m.length,
+		Slots:      slots,
+		LoadFactor: float64(m.length) / float64(slots),
+		Stash:      len(m.stash),
+		Rehashes:   m.rehashes,
+		MaxProbes:  len(m.seeds)*slotsPerBucket + stashSize,
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+const benchN = 1 << 16
+
+func benchLookup(m Map[int, int], offset int) func(b *testing.B) {
+	return func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.Get(offset + i&(benchN-1))
+		}
+	}
+}
+
+func main() {
+	for ways := 2; ways <= 4; ways++ {
+		if err := conformance(func() Map[int, int] { return NewCuckooHash[int, int](ways) }); err != nil {
+			fmt.Println(ways, "个哈希函数：一致性检查失败：", err)
+			return
+		}
+		fmt.Println(ways, "个哈希函数：一致性检查通过")
+	}
+
+	// 装满到扩容阈值附近，观察装载因子、stash 和重建次数
+	for ways := 2; ways <= 4; ways++ {
+		m := NewCuckooHash[int, int](ways)
+		for i := 0; i < 117000; i++ { // 131072 个槽的 89%
+			m.Put(i, i)
+		}
+		fmt.Printf("%d 个哈希函数：%+v\n", ways, m.Stats())
+	}
+
+	builtin := make(map[int]int)
+	cuckoo := NewCuckooHash[int, int](2)
+	for i := 0; i < benchN; i++ {
+		builtin[i] = i
+		cuckoo.Put(i, i)
+	}
+	for _, bench := range []struct {
+		name   string
+		offset int
+	}{
+		{"查找命中", 0},
+		{"查找未命中", benchN},
+	} {
+		r := testing.Benchmark(benchLookup(cuckoo, bench.offset))
+		fmt.Printf("%s 布谷鸟哈希: %s\n", bench.name, r)
+		r = testing.Benchmark(func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = builtin[bench.offset+i&(benchN-1)]
+			}
+		})
+		fmt.Printf("%s 内置 map: %s\n", bench.name, r)
+	}
+}
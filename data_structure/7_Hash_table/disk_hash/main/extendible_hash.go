@@ -0,0 +1,193 @@
+package main
+
+import "fmt"
+
+// 可扩展哈希（Fagin 等, 1979）：用哈希值的低 globalDepth 位索引一个目录，目录项指向桶页，
+// 多个目录项可以指向同一个桶页。每个桶页记录自己的局部深度 depth，表示桶内所有键的低 depth 位相同。
+// 桶满时只分裂这一个桶：depth 加 1，按第 depth 位把记录分到旧页和新页，
+// 并把原来指向旧页、该位为 1 的目录项改为指向新页。depth 已经等于 globalDepth 时先把目录加倍，
+// 加倍只复制页号，不移动任何记录。没有溢出页，查找恰好读一个桶页。
+// 桶页、目录和元数据都在 Flush/Close 时写回。删除不合并桶。
+
+const (
+	extendibleMagic    = "EXTH"
+	maxGlobalDepth     = 24 // 目录最多 2^24 项，防止大量哈希值相同的键让目录无限加倍
+	extendibleMinDepth = 2
+)
+
+type ExtendibleHash struct {
+	pager
+	globalDepth uint
+	dir         []PageID
+	count       int
+	buckets     int
+}
+
+// OpenExtendibleHash 在 store 上打开可扩展哈希索引，store 为空时新建
+func OpenExtendibleHash(store PageStore) (*ExtendibleHash, error) {
+	m := &ExtendibleHash{pager: newPager(store)}
+	if store.NumPages() == 0 {
+		if _, err := store.Allocate(); err != nil { // 元数据页
+			return nil, err
+		}
+		m.globalDepth = extendibleMinDepth
+		for i := 0; i < 1<<extendibleMinDepth; i++ {
+			b, err := m.alloc()
+			if err != nil {
+				return nil, err
+			}
+			b.depth = extendibleMinDepth
+			if err := m.writeBucket(b); err != nil {
+				return nil, err
+			}
+			m.dir = append(m.dir, b.id)
+		}
+		m.buckets = len(m.dir)
+		return m, m.Flush()
+	}
+	f, err := m.readMeta(extendibleMagic, 4)
+	if err != nil {
+		return nil, err
+	}
+	m.globalDepth, m.count, m.buckets = uint(f[0]), int(f[1]), int(f[2])
+	if m.dir, err = m.loadTable(PageID(f[3])); err != nil {
+		return nil, err
+	}
+	if len(m.dir) != 1<<m.globalDepth {
+		return nil, fmt.Errorf("directory has %d entries, want %d", len(m.dir), 1<<m.globalDepth)
+	}
+	return m, nil
+}
+
+// Flush 写回目录和元数据
+func (m *ExtendibleHash) Flush() error {
+	head, err := m.saveTable(m.dir)
+	if err != nil {
+		return err
+	}
+	return m.writeMeta(extendibleMagic, uint64(m.globalDepth), uint64(m.count), uint64(m.buckets), uint64(head))
+}
+
+func (m *ExtendibleHash) Close() error {
+	if err := m.Flush(); err != nil {
+		m.store.Close()
+		return err
+	}
+	return m.store.Close()
+}
+
+func (m *ExtendibleHash) Len() int {
+	return m.count
+}
+
+func (m *ExtendibleHash) bucketOf(h uint64) int {
+	return int(h & (1<<m.globalDepth - 1))
+}
+
+func (m *ExtendibleHash) Get(key []byte) ([]byte, bool, error) {
+	b, err := m.readBucket(m.dir[m.bucketOf(hashKey(key))])
+	if err != nil {
+		return nil, false, err
+	}
+	if i := b.find(key); i >= 0 {
+		return b.records[i].value, true, nil
+	}
+	return nil, false, nil
+}
+
+func (m *ExtendibleHash) Put(key, value []byte) error {
+	if len(key)+len(value) > maxRecordSize {
+		return errRecordTooLarge
+	}
+	r := record{append([]byte(nil), key...), append([]byte(nil), value...)}
+	h := hashKey(key)
+	for {
+		b, err := m.readBucket(m.dir[m.bucketOf(h)])
+		if err != nil {
+			return err
+		}
+		// 旧记录腾出的空间也算在内，确定新记录放得下之后才删除旧记录，
+		// 分裂失败时桶和计数都保持原样
+		i := b.find(key)
+		free := b.free()
+		if i >= 0 {
+			free += b.records[i].size()
+		}
+		if free >= r.size() {
+			if i >= 0 {
+				b.remove(i)
+			} else {
+				m.count++
+			}
+			b.add(r)
+			return m.writeBucket(b)
+		}
+		// 放不下，分裂后重试
+		if err := m.splitBucket(b); err != nil {
+			return err
+		}
+	}
+}
+
+// 分裂桶页 b
+func (m *ExtendibleHash) splitBucket(b *bucketPage) error {
+	if uint(b.depth) == m.globalDepth {
+		if m.globalDepth == maxGlobalDepth {
+			return fmt.Errorf("too many keys share the same %d hash bits", maxGlobalDepth)
+		}
+		m.dir = append(m.dir, m.dir...)
+		m.globalDepth++
+	}
+	n, err := m.alloc()
+	if err != nil {
+		return err
+	}
+	bit := uint64(1) << b.depth
+	b.depth++
+	n.depth = b.depth
+	records := b.records
+	b.records, b.used = nil, 0
+	for _, r := range records {
+		if hashKey(r.key)&bit != 0 {
+			n.add(r)
+		} else {
+			b.add(r)
+		}
+	}
+	for i, id := range m.dir {
+		if id == b.id && uint64(i)&bit != 0 {
+			m.dir[i] = n.id
+		}
+	}
+	m.buckets++
+	if err := m.writeBucket(b); err != nil {
+		return err
+	}
+	return m.writeBucket(n)
+}
+
+func (m *ExtendibleHash) Delete(key []byte) (bool, error) {
+	b, err := m.readBucket(m.dir[m.bucketOf(hashKey(key))])
+	if err != nil {
+		return false, err
+	}
+	i := b.find(key)
+	if i < 0 {
+		return false, nil
+	}
+	b.remove(i)
+	m.count--
+	return true, m.writeBucket(b)
+}
+
+// ExtendibleStats 可扩展哈希索引的内部状态
+type ExtendibleStats struct {
+	Len         int
+	Buckets     int
+	GlobalDepth uint
+	DirEntries  int
+}
+
+func (m *ExtendibleHash) Stats() ExtendibleStats {
+	return ExtendibleStats{m.count, m.buckets, m.globalDepth, len(m.dir)}
+}
@@ -0,0 +1,249 @@
+package main
+
+// 线性哈希（Litwin, 1980）：桶数不必是 2 的幂，每次只分裂一个桶。
+// 设初始桶数为 N，当前轮次为 level，本轮桶数 n = N·2^level，分裂指针 split 指向下一个要分裂的桶。
+// 键的桶号先按 h mod n 计算，结果小于 split 说明那个桶本轮已经分裂过，改用 h mod 2n。
+// 装载因子超过阈值时分裂 split 指向的桶（不一定是刚插入的那个），它的记录按 h mod 2n
+// 留在原桶或移到新追加的桶 split+n 中，然后 split 加 1；split 到达 n 时进入下一轮。
+// 尚未轮到分裂的桶用溢出页链表容纳多出来的记录。
+// 桶页、元数据和映射表都在 Flush/Close 时写回。
+
+const (
+	linearMagic          = "LINH"
+	linearInitialBuckets = 4
+	linearMaxLoad        = 0.75 // 记录字节数占桶页空间的比例
+)
+
+type LinearHash struct {
+	pager
+	level    uint
+	split    int
+	buckets  []PageID // 每个桶的主页
+	count    int      // 记录条数
+	bytes    int      // 记录总字节数，用于计算装载因子
+	overflow int      // 溢出页个数
+}
+
+// OpenLinearHash 在 store 上打开线性哈希索引，store 为空时新建
+func OpenLinearHash(store PageStore) (*LinearHash, error) {
+	m := &LinearHash{pager: newPager(store)}
+	if store.NumPages() == 0 {
+		if _, err := store.Allocate(); err != nil { // 元数据页
+			return nil, err
+		}
+		for i := 0; i < linearInitialBuckets; i++ {
+			b, err := m.alloc()
+			if err != nil {
+				return nil, err
+			}
+			m.buckets = append(m.buckets, b.id)
+		}
+		return m, m.Flush()
+	}
+	f, err := m.readMeta(linearMagic, 7)
+	if err != nil {
+		return nil, err
+	}
+	m.level, m.split, m.count, m.bytes, m.overflow = uint(f[0]), int(f[1]), int(f[2]), int(f[3]), int(f[4])
+	m.freeHead = PageID(f[5])
+	if m.buckets, err = m.loadTable(PageID(f[6])); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Flush 写回元数据和映射表
+func (m *LinearHash) Flush() error {
+	head, err := m.saveTable(m.buckets)
+	if err != nil {
+		return err
+	}
+	return m.writeMeta(linearMagic, uint64(m.level), uint64(m.split), uint64(m.count),
+		uint64(m.bytes), uint64(m.overflow), uint64(m.freeHead), uint64(head))
+}
+
+func (m *LinearHash) Close() error {
+	if err := m.Flush(); err != nil {
+		m.store.Close()
+		return err
+	}
+	return m.store.Close()
+}
+
+func (m *LinearHash) Len() int {
+	return m.count
+}
+
+func (m *LinearHash) bucketOf(h uint64) int {
+	n := uint64(linearInitialBuckets) << m.level
+	i := h % n
+	if i < uint64(m.split) {
+		i = h % (2 * n)
+	}
+	return int(i)
+}
+
+// 在桶的页链表中查找键，返回所在页及其前驱页（前驱为 nil 表示在主页）
+func (m *LinearHash) find(key []byte) (page, prev *bucketPage, i int, err error) {
+	id := m.buckets[m.bucketOf(hashKey(key))]
+	for id != 0 {
+		b, err := m.readBucket(id)
+		if err != nil {
+			return nil, nil, -1, err
+		}
+		if i := b.find(key); i >= 0 {
+			return b, prev, i, nil
+		}
+		prev, id = b, b.next
+	}
+	return nil, nil, -1, nil
+}
+
+func (m *LinearHash) Get(key []byte) ([]byte, bool, error) {
+	b, _, i, err := m.find(key)
+	if b == nil {
+		return nil, false, err
+	}
+	return b.records[i].value, true, nil
+}
+
+func (m *LinearHash) Put(key, value []byte) error {
+	r := record{append([]byte(nil), key...), append([]byte(nil), value...)}
+	if len(key)+len(value) > maxRecordSize {
+		return errRecordTooLarge
+	}
+	b, prev, i, err := m.find(key)
+	if err != nil {
+		return err
+	}
+	if b != nil {
+		old := b.remove(i)
+		m.count--
+		m.bytes -= old.size()
+		if b.free() >= r.size() {
+			b.add(r)
+			m.count++
+			m.bytes += r.size()
+			return m.writeBucket(b)
+		}
+		if err := m.removed(b, prev); err != nil {
+			return err
+		}
+	}
+	if err := m.insert(m.bucketOf(hashKey(key)), r); err != nil {
+		return err
+	}
+	m.count++
+	m.bytes += r.size()
+	if float64(m.bytes) > linearMaxLoad*float64(len(m.buckets)*bucketSpace) {
+		return m.splitBucket()
+	}
+	return nil
+}
+
+// 把记录放入桶中第一个放得下的页，都放不下时追加溢出页
+func (m *LinearHash) insert(bucket int, r record) error {
+	var last *bucketPage
+	for id := m.buckets[bucket]; id != 0; {
+		b, err := m.readBucket(id)
+		if err != nil {
+			return err
+		}
+		if b.free() >= r.size() {
+			b.add(r)
+			return m.writeBucket(b)
+		}
+		last, id = b, b.next
+	}
+	b, err := m.alloc()
+	if err != nil {
+		return err
+	}
+	b.add(r)
+	if err := m.writeBucket(b); err != nil {
+		return err
+	}
+	m.overflow++
+	last.next = b.id
+	return m.writeBucket(last)
+}
+
+// 页中删除了记录后写回；溢出页空了就从链表中摘下并释放
+func (m *LinearHash) removed(b, prev *bucketPage) error {
+	if prev == nil || len(b.records) > 0 {
+		return m.writeBucket(b)
+	}
+	prev.next = b.next
+	if err := m.writeBucket(prev); err != nil {
+		return err
+	}
+	m.overflow--
+	return m.release(b.id)
+}
+
+func (m *LinearHash) Delete(key []byte) (bool, error) {
+	b, prev, i, err := m.find(key)
+	if b == nil {
+		return false, err
+	}
+	r := b.remove(i)
+	m.count--
+	m.bytes -= r.size()
+	return true, m.removed(b, prev)
+}
+
+// 分裂 split 指向的桶
+func (m *LinearHash) splitBucket() error {
+	var records []record
+	id := m.buckets[m.split]
+	for first := true; id != 0; first = false {
+		b, err := m.readBucket(id)
+		if err != nil {
+			return err
+		}
+		records = append(records, b.records...)
+		if !first {
+			if err := m.release(id); err != nil {
+				return err
+			}
+			m.overflow--
+		}
+		id = b.next
+	}
+	if err := m.writeBucket(&bucketPage{id: m.buckets[m.split]}); err != nil {
+		return err
+	}
+	b, err := m.alloc()
+	if err != nil {
+		return err
+	}
+	if err := m.writeBucket(b); err != nil {
+		return err
+	}
+	m.buckets = append(m.buckets, b.id)
+
+	m.split++
+	if m.split == linearInitialBuckets<<m.level {
+		m.level++
+		m.split = 0
+	}
+	for _, r := range records {
+		if err := m.insert(m.bucketOf(hashKey(r.key)), r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LinearStats 线性哈希索引的内部状态
+type LinearStats struct {
+	Len           int
+	Buckets       int
+	OverflowPages int
+	Level         uint
+	Split         int
+}
+
+func (m *LinearHash) Stats() LinearStats {
+	return LinearStats{m.count, len(m.buckets), m.overflow, m.level, m.split}
+}
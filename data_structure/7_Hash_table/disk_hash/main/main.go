@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// Index 两种磁盘哈希索引共同的接口
+type Index interface {
+	Get(key []byte) ([]byte, bool, error)
+	Put(key, value []byte) error
+	Delete(key []byte) (bool, error)
+	Len() int
+	Flush() error
+	Close() error
+}
+
+// 与内置 map 对照的随机操作，返回第一个不一致的地方
+func check(idx Index, want map[string]string, steps int, rng *rand.Rand) error {
+	for i := 0; i < steps; i++ {
+		key := fmt.Sprintf("addr-%05d", rng.Intn(20000))
+		switch rng.Intn(4) {
+		case 0, 1:
+			value := bytes.Repeat([]byte{byte('a' + i%26)}, rng.Intn(100))
+			if err := idx.Put([]byte(key), value); err != nil {
+				return err
+			}
+			want[key] = string(value)
+		case 2:
+			ok, err := idx.Delete([]byte(key))
+			if err != nil {
+				return err
+			}
+			if _, wok := want[key]; ok != wok {
+				return fmt.Errorf("step %d: Delete(%s) = %v", i, key, ok)
+			}
+			delete(want, key)
+		case 3:
+			v, ok, err := idx.Get([]byte(key))
+			if err != nil {
+				return err
+			}
+			if w, wok := want[key]; ok != wok || string(v) != w {
+				return fmt.Errorf("step %d: Get(%s) = %q %v", i, key, v, ok)
+			}
+		}
+		if idx.Len() != len(want) {
+			return fmt.Errorf("step %d: Len %d, want %d", i, idx.Len(), len(want))
+		}
+	}
+	return nil
+}
+
+// 检查索引中的内容与 want 完全一致
+func verify(idx Index, want map[string]string) error {
+	if idx.Len() != len(want) {
+		return fmt.Errorf("Len %d, want %d", idx.Len(), len(want))
+	}
+	for k, w := range want {
+		v, ok, err := idx.Get([]byte(k))
+		if err != nil {
+			return err
+		}
+		if !ok || string(v) != w {
+			return fmt.Errorf("Get(%s) = %q %v, want %q", k, v, ok, w)
+		}
+	}
+	return nil
+}
+
+func main() {
+	dir, err := os.MkdirTemp("", "disk_hash")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	kinds := []struct {
+		name string
+		open func(PageStore) (Index, error)
+	}{
+		{"线性哈希", func(s PageStore) (Index, error) { return OpenLinearHash(s) }},
+		{"可扩展哈希", func(s PageStore) (Index, error) { return OpenExtendibleHash(s) }},
+	}
+
+	for _, kind := range kinds {
+		// 内存页存储
+		idx, err := kind.open(NewMemoryStore())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		want := make(map[string]string)
+		if err := check(idx, want, 100000, rand.New(rand.NewSource(1))); err != nil {
+			fmt.Println(kind.name, "内存：", err)
+			return
+		}
+		fmt.Printf("%s 内存：随机测试通过 %+v\n", kind.name, stats(idx))
+
+		// 文件页存储：写入、关闭后重新打开，内容不变，可以继续写
+		path := filepath.Join(dir, kind.name+".db")
+		store, err := OpenFileStore(path)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if idx, err = kind.open(store); err != nil {
+			fmt.Println(err)
+			return
+		}
+		want = make(map[string]string)
+		rng := rand.New(rand.NewSource(2))
+		if err := check(idx, want, 50000, rng); err != nil {
+			fmt.Println(kind.name, "文件：", err)
+			return
+		}
+		if err := idx.Close(); err != nil {
+			fmt.Println(err)
+			return
+		}
+		for round := 0; round < 2; round++ {
+			if store, err = OpenFileStore(path); err != nil {
+				fmt.Println(err)
+				return
+			}
+			if idx, err = kind.open(store); err != nil {
+				fmt.Println(err)
+				return
+			}
+			if err := verify(idx, want); err != nil {
+				fmt.Println(kind.name, "重新打开后：", err)
+				return
+			}
+			if err := check(idx, want, 20000, rng); err != nil {
+				fmt.Println(kind.name, "重新打开后：", err)
+				return
+			}
+			fmt.Printf("%s 文件：重新打开后内容一致 %+v，%d 页\n", kind.name, stats(idx), store.NumPages())
+			if err := idx.Close(); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+
+		// 模拟崩溃：Flush 之后继续写入，不写回就关闭文件，重新打开后是 Flush 时的内容
+		if store, err = OpenFileStore(path); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if idx, err = kind.open(store); err != nil {
+			fmt.Println(err)
+			return
+		}
+		flushed := maps.Clone(want)
+		if err := check(idx, want, 5000, rng); err != nil {
+			fmt.Println(kind.name, "崩溃前：", err)
+			return
+		}
+		store.file.Close()
+		if store, err = OpenFileStore(path); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if idx, err = kind.open(store); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := verify(idx, flushed); err != nil {
+			fmt.Println(kind.name, "崩溃后：", err)
+			return
+		}
+		fmt.Printf("%s 文件：崩溃后恢复到上次 Flush 的内容 %+v\n", kind.name, stats(idx))
+		idx.Close()
+	}
+
+	// 类型不匹配的页文件
+	store, _ := OpenFileStore(filepath.Join(dir, "线性哈希.db"))
+	_, err = OpenExtendibleHash(store)
+	fmt.Println(err)
+	store.Close()
+
+	// 超长记录
+	idx, _ := OpenLinearHash(NewMemoryStore())
+	fmt.Println(idx.Put([]byte("k"), make([]byte, PageSize)))
+}
+
+func stats(idx Index) any {
+	switch idx := idx.(type) {
+	case *LinearHash:
+		return idx.Stats()
+	case *ExtendibleHash:
+		return idx.Stats()
+	}
+	return nil
+}
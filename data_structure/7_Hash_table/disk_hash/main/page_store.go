@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// 磁盘上的数据按固定大小的页读写。哈希索引只通过 PageStore 接口访问页，
+// 同一份代码既可以跑在内存中，也可以跑在文件上。
+
+const PageSize = 4096
+
+// PageID 页号，页 0 固定用作索引的元数据页
+type PageID uint32
+
+type PageStore interface {
+	// NumPages 返回已分配的页数
+	NumPages() int
+	// Allocate 在末尾分配一个新页，内容全为 0
+	Allocate() (PageID, error)
+	// ReadPage 把页 id 的内容读入 buf，buf 的长度为 PageSize
+	ReadPage(id PageID, buf []byte) error
+	// WritePage 把 buf 写入页 id
+	WritePage(id PageID, buf []byte) error
+	Close() error
+}
+
+var errPageOutOfRange = errors.New("page out of range")
+
+// MemoryStore 内存中的页存储
+type MemoryStore struct {
+	pages [][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) NumPages() int {
+	return len(s.pages)
+}
+
+func (s *MemoryStore) Allocate() (PageID, error) {
+	s.pages = append(s.pages, make([]byte, PageSize))
+	return PageID(len(s.pages) - 1), nil
+}
+
+func (s *MemoryStore) ReadPage(id PageID, buf []byte) error {
+	if int(id) >= len(s.pages) {
+		return errPageOutOfRange
+	}
+	copy(buf, s.pages[id])
+	return nil
+}
+
+func (s *MemoryStore) WritePage(id PageID, buf []byte) error {
+	if int(id) >= len(s.pages) {
+		return errPageOutOfRange
+	}
+	copy(s.pages[id], buf)
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// FileStore 文件上的页存储，页 id 位于文件偏移 id*PageSize 处
+type FileStore struct {
+	file     *os.File
+	numPages int
+}
+
+// OpenFileStore 打开或创建页文件
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size()%PageSize != 0 {
+		f.Close()
+		return nil, fmt.Errorf("%s: size %d is not a multiple of page size", path, info.Size())
+	}
+	return &FileStore{file: f, numPages: int(info.Size() / PageSize)}, nil
+}
+
+func (s *FileStore) NumPages() int {
+	return s.numPages
+}
+
+func (s *FileStore) Allocate() (PageID, error) {
+	id := PageID(s.numPages)
+	if err := s.file.Truncate(int64(s.numPages+1) * PageSize); err != nil {
+		return 0, err
+	}
+	s.numPages++
+	return id, nil
+}
+
+func (s *FileStore) ReadPage(id PageID, buf []byte) error {
+	if int(id) >= s.numPages {
+		return errPageOutOfRange
+	}
+	_, err := s.file.ReadAt(buf[:PageSize], int64(id)*PageSize)
+	return err
+}
+
+func (s *FileStore) WritePage(id PageID, buf []byte) error {
+	if int(id) >= s.numPages {
+		return errPageOutOfRange
+	}
+	_, err := s.file.WriteAt(buf[:PageSize], int64(id)*PageSize)
+	return err
+}
+
+// Close 把数据刷到磁盘并关闭文件
+func (s *FileStore) Close() error {
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
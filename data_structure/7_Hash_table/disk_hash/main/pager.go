@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+)
+
+// 两种磁盘哈希索引共用的页格式。
+//
+// 桶页：
+//
+//	[0:4)  next   溢出页链表 / 空闲页链表中的下一页，0 表示没有
+//	[4:6)  depth  局部深度，只有可扩展哈希使用
+//	[6:8)  count  记录条数
+//	之后依次是记录：keyLen(2) valueLen(2) key value
+//
+// 元数据页（页 0）：magic(4) 之后是若干 uint64 字段。
+// 桶号到页号的映射表存放在一串表页中：next(4) count(4) 之后是 count 个页号。
+//
+// 修改过的桶页和表页先缓存在内存中，Flush 时全部写回，最后才写元数据页。
+// 两次 Flush 之间崩溃，磁盘上仍是上一次 Flush 后的完整索引，可能多出一些没有被引用的新页；
+// Flush 写到一半时崩溃仍可能不一致，要做到原子提交需要预写日志或影子页，这里没有实现。
+// 缓存的页数没有上限，大批量写入时应定期 Flush。
+
+const (
+	bucketHeaderSize = 8
+	recordHeaderSize = 4
+	bucketSpace      = PageSize - bucketHeaderSize
+	maxRecordSize    = bucketSpace - recordHeaderSize // key 与 value 的总长度上限
+	tableHeaderSize  = 8
+	tablePageEntries = (PageSize - tableHeaderSize) / 4
+	metaPage         = PageID(0)
+)
+
+var (
+	errRecordTooLarge = fmt.Errorf("key and value must be at most %d bytes in total", maxRecordSize)
+	errBadMagic       = errors.New("page store does not hold this kind of index")
+)
+
+// 键的哈希值决定它在哪个桶，随索引一起固化在磁盘上。
+// FNV-1a 的低位分布不够均匀，再用 splitmix64 的终结函数打散
+func hashKey(key []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(key)
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type record struct {
+	key   []byte
+	value []byte
+}
+
+func (r record) size() int {
+	return recordHeaderSize + len(r.key) + len(r.value)
+}
+
+// 内存中的桶页
+type bucketPage struct {
+	id      PageID
+	next    PageID
+	depth   uint16
+	records []record
+	used    int // 记录占用的字节数
+}
+
+func (b *bucketPage) free() int {
+	return bucketSpace - b.used
+}
+
+func (b *bucketPage) find(key []byte) int {
+	for i, r := range b.records {
+		if bytes.Equal(r.key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *bucketPage) add(r record) {
+	b.records = append(b.records, r)
+	b.used += r.size()
+}
+
+func (b *bucketPage) remove(i int) record {
+	r := b.records[i]
+	b.records = append(b.records[:i], b.records[i+1:]...)
+	b.used -= r.size()
+	return r
+}
+
+func (b *bucketPage) encode(buf []byte) {
+	clear(buf)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(b.next))
+	binary.LittleEndian.PutUint16(buf[4:], b.depth)
+	binary.LittleEndian.PutUint16(buf[6:], uint16(len(b.records)))
+	off := bucketHeaderSize
+	for _, r := range b.records {
+		binary.LittleEndian.PutUint16(buf[off:], uint16(len(r.key)))
+		binary.LittleEndian.PutUint16(buf[off+2:], uint16(len(r.value)))
+		off += recordHeaderSize
+		off += copy(buf[off:], r.key)
+		off += copy(buf[off:], r.value)
+	}
+}
+
+func decodeBucket(id PageID, buf []byte) (*bucketPage, error) {
+	b := &bucketPage{
+		id:    id,
+		next:  PageID(binary.LittleEndian.Uint32(buf[0:])),
+		depth: binary.LittleEndian.Uint16(buf[4:]),
+	}
+	n := int(binary.LittleEndian.Uint16(buf[6:]))
+	off := bucketHeaderSize
+	for i := 0; i < n; i++ {
+		if off+recordHeaderSize > PageSize {
+			return nil, fmt.Errorf("page %d: corrupt record header", id)
+		}
+		klen := int(binary.LittleEndian.Uint16(buf[off:]))
+		vlen := int(binary.LittleEndian.Uint16(buf[off+2:]))
+		off += recordHeaderSize
+		if off+klen+vlen > PageSize {
+			return nil, fmt.Errorf("page %d: corrupt record", id)
+		}
+		// 复制出来，不引用 buf
+		data := append([]byte(nil), buf[off:off+klen+vlen]...)
+		b.add(record{data[:klen:klen], data[klen:]})
+		off += klen + vlen
+	}
+	return b, nil
+}
+
+// pager 在 PageStore 之上管理桶页、空闲页和元数据
+type pager struct {
+	store      PageStore
+	buf        []byte
+	dirty      map[PageID][]byte // 尚未写回的页
+	freeHead   PageID            // 空闲页链表头
+	tablePages []PageID          // 映射表占用的页
+	table      []PageID          // 表页中已经保存的映射表
+}
+
+func newPager(store PageStore) pager {
+	return pager{store: store, buf: make([]byte, PageSize), dirty: make(map[PageID][]byte)}
+}
+
+// 读页，优先读缓存中尚未写回的内容
+func (p *pager) readPage(id PageID) ([]byte, error) {
+	if page, ok := p.dirty[id]; ok {
+		return page, nil
+	}
+	if err := p.store.ReadPage(id, p.buf); err != nil {
+		return nil, err
+	}
+	return p.buf, nil
+}
+
+// 把页放入缓存，Flush 时写回
+func (p *pager) writePage(id PageID, page []byte) {
+	p.dirty[id] = page
+}
+
+func (p *pager) readBucket(id PageID) (*bucketPage, error) {
+	page, err := p.readPage(id)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBucket(id, page)
+}
+
+func (p *pager) writeBucket(b *bucketPage) error {
+	page := make([]byte, PageSize)
+	b.encode(page)
+	p.writePage(b.id, page)
+	return nil
+}
+
+// 分配一个空桶页，优先复用空闲页
+func (p *pager) alloc() (*bucketPage, error) {
+	if p.freeHead != 0 {
+		b, err := p.readBucket(p.freeHead)
+		if err != nil {
+			return nil, err
+		}
+		p.freeHead = b.next
+		return &bucketPage{id: b.id}, nil
+	}
+	id, err := p.store.Allocate()
+	if err != nil {
+		return nil, err
+	}
+	return &bucketPage{id: id}, nil
+}
+
+// 把页放回空闲链表
+func (p *pager) release(id PageID) error {
+	b := &bucketPage{id: id, next: p.freeHead}
+	if err := p.writeBucket(b); err != nil {
+		return err
+	}
+	p.freeHead = id
+	return nil
+}
+
+// 按页号顺序写回缓存的页，最后写元数据页，使磁盘上的索引整体切换到新的状态
+func (p *pager) writeMeta(magic string, fields ...uint64) error {
+	for _, id := range slices.Sorted(maps.Keys(p.dirty)) {
+		if err := p.store.WritePage(id, p.dirty[id]); err != nil {
+			return err
+		}
+		delete(p.dirty, id)
+	}
+	clear(p.buf)
+	copy(p.buf, magic)
+	for i, f := range fields {
+		binary.LittleEndian.PutUint64(p.buf[4+8*i:], f)
+	}
+	return p.store.WritePage(metaPage, p.buf)
+}
+
+// 读元数据页，返回 n 个字段
+func (p *pager) readMeta(magic string, n int) ([]uint64, error) {
+	if err := p.store.ReadPage(metaPage, p.buf); err != nil {
+		return nil, err
+	}
+	if string(p.buf[:4]) != magic {
+		return nil, errBadMagic
+	}
+	fields := make([]uint64, n)
+	for i := range fields {
+		fields[i] = binary.LittleEndian.Uint64(p.buf[4+8*i:])
+	}
+	return fields, nil
+}
+
+// 映射表中第 i 个表页的内容
+func tableChunk(ids []PageID, i int) []PageID {
+	return ids[min(len(ids), i*tablePageEntries):min(len(ids), (i+1)*tablePageEntries)]
+}
+
+// 把映射表写入表页，表页不够时追加，返回第一个表页。
+// 只重写内容或下一页指针有变化的表页
+func (p *pager) saveTable(ids []PageID) (PageID, error) {
+	need := max(1, (len(ids)+tablePageEntries-1)/tablePageEntries)
+	old := len(p.tablePages)
+	for len(p.tablePages) < need {
+		b, err := p.alloc()
+		if err != nil {
+			return 0, err
+		}
+		p.tablePages = append(p.tablePages, b.id)
+	}
+	for i, id := range p.tablePages {
+		chunk := tableChunk(ids, i)
+		// 原来的最后一个表页在追加了新表页后，下一页指针会变
+		nextChanged := i+1 == old && old < len(p.tablePages)
+		if i < old && !nextChanged && slices.Equal(chunk, tableChunk(p.table, i)) {
+			continue
+		}
+		page := make([]byte, PageSize)
+		if i+1 < len(p.tablePages) {
+			binary.LittleEndian.PutUint32(page[0:], uint32(p.tablePages[i+1]))
+		}
+		binary.LittleEndian.PutUint32(page[4:], uint32(len(chunk)))
+		for j, bid := range chunk {
+			binary.LittleEndian.PutUint32(page[tableHeaderSize+4*j:], uint32(bid))
+		}
+		p.writePage(id, page)
+	}
+	p.table = slices.Clone(ids)
+	return p.tablePages[0], nil
+}
+
+// 从 head 开始读出映射表
+func (p *pager) loadTable(head PageID) ([]PageID, error) {
+	var ids []PageID
+	p.tablePages = nil
+	for id := head; id != 0; {
+		if err := p.store.ReadPage(id, p.buf); err != nil {
+			return nil, err
+		}
+		p.tablePages = append(p.tablePages, id)
+		n := int(binary.LittleEndian.Uint32(p.buf[4:]))
+		if n > tablePageEntries {
+			return nil, fmt.Errorf("page %d: corrupt table page", id)
+		}
+		for j := 0; j < n; j++ {
+			ids = append(ids, PageID(binary.LittleEndian.Uint32(p.buf[tableHeaderSize+4*j:])))
+		}
+		id = PageID(binary.LittleEndian.Uint32(p.buf[0:]))
+	}
+	p.table = slices.Clone(ids)
+	return ids, nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// 链地址法哈希表，扩容和缩容采用与 Redis dict 相同的渐进式 rehash：
+// 需要调整大小时并不一次性搬迁所有元素，而是同时保留新旧两张表，
+// 之后每次 Get/Put/Delete 顺带搬迁旧表中的一个桶，把搬迁的开销分摊到各次操作上，
+// 避免大表扩容时出现长时间停顿。rehash 期间新元素只插入新表，查找和删除两张表都要看。
+// 每个哈希表创建时随机生成哈希种子，攻击者无法预先构造出大量冲突的键（哈希泛洪攻击）。
+
+const (
+	minBuckets = 8 // 桶数组的最小长度
+	maxLoad    = 1 // 元素个数达到桶数的 maxLoad 倍时扩容
+	minLoad    = 8 // 元素个数不足桶数的 1/minLoad 时缩容
+	emptyVisit = 10
+)
+
+// Hasher 计算键的哈希值，seed 为哈希表的随机种子，相同的种子和键必须得到相同的结果
+type Hasher[K comparable] func(seed maphash.Seed, key K) uint64
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	hash  uint64 // 缓存哈希值，rehash 时不必重新计算
+	next  *entry[K, V]
+}
+
+type table[K comparable, V any] struct {
+	buckets []*entry[K, V] // 长度为 2 的幂
+	used    int
+}
+
+func newTable[K comparable, V any](size int) *table[K, V] {
+	return &table[K, V]{buckets: make([]*entry[K, V], size)}
+}
+
+func (t *table[K, V]) index(hash uint64) uint64 {
+	return hash & uint64(len(t.buckets)-1)
+}
+
+// HashMap 泛型哈希表
+type HashMap[K comparable, V any] struct {
+	hasher    Hasher[K]
+	seed      maphash.Seed
+	tables    [2]*table[K, V] // tables[1] 只在 rehash 期间不为 nil
+	rehashIdx int             // 旧表中下一个待搬迁的桶
+	iterating int             // 正在进行的 Range 个数，不为 0 时暂停 rehash
+}
+
+// NewHashMap 创建一个使用 maphash 计算哈希值的哈希表
+func NewHashMap[K comparable, V any]() *HashMap[K, V] {
+	return NewHashMapFunc[K, V](maphash.Comparable[K])
+}
+
+// NewHashMapFunc 创建一个使用自定义哈希函数的哈希表
+func NewHashMapFunc[K comparable, V any](hasher Hasher[K]) *HashMap[K, V] {
+	return &HashMap[K, V]{
+		hasher: hasher,
+		seed:   maphash.MakeSeed(),
+		tables: [2]*table[K, V]{newTable[K, V](minBuckets)},
+	}
+}
+
+// Len 返回元素个数
+func (m *HashMap[K, V]) Len() int {
+	n := m.tables[0].used
+	if m.tables[1] != nil {
+		n += m.tables[1].used
+	}
+	return n
+}
+
+func (m *HashMap[K, V]) rehashing() bool {
+	return m.tables[1] != nil
+}
+
+// 开始 rehash 到长度为 size 的新表
+func (m *HashMap[K, V]) startRehash(size int) {
+	if size == len(m.tables[0].buckets) {
+		return
+	}
+	m.tables[1] = newTable[K, V](size)
+	m.rehashIdx = 0
+}
+
+// 搬迁旧表中的 n 个非空桶。为了避免旧表很稀疏时单次操作耗时过长，最多访问 n*emptyVisit 个空桶
+func (m *HashMap[K, V]) rehashStep(n int) {
+	if !m.rehashing() || m.iterating > 0 {
+		return
+	}
+	old, cur := m.tables[0], m.tables[1]
+	empty := n * emptyVisit
+	for n > 0 && old.used > 0 {
+		e := old.buckets[m.rehashIdx]
+		if e == nil {
+			m.rehashIdx++
+			if empty--; empty == 0 {
+				return
+			}
+			continue
+		}
+		for e != nil {
+			next := e.next
+			i := cur.index(e.hash)
+			e.next = cur.buckets[i]
+			cur.buckets[i] = e
+			old.used--
+			cur.used++
+			e = next
+		}
+		old.buckets[m.rehashIdx] = nil
+		m.rehashIdx++
+		n--
+	}
+	if old.used == 0 {
+		m.tables[0], m.tables[1] = cur, nil
+		m.rehashIdx = 0
+	}
+}
+
+// 在两张表中查找键，返回指向该节点的指针所在的位置，便于删除
+func (m *HashMap[K, V]) find(key K, hash uint64) (*table[K, V], **entry[K, V]) {
+	for _, t := range m.tables {
+		if t == nil {
+			break
+		}
+		for p := &t.buckets[t.index(hash)]; *p != nil; p = &(*p).next {
+			if (*p).hash == hash && (*p).key == key {
+				return t, p
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Get 返回键对应的值
+func (m *HashMap[K, V]) Get(key K) (V, bool) {
+	m.rehashStep(1)
+	if _, p := m.find(key, m.hasher(m.seed, key)); p != nil {
+		return (*p).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put 插入或更新键值对
+func (m *HashMap[K, V]) Put(key K, value V) {
+	m.rehashStep(1)
+	hash := m.hasher(m.seed, key)
+	if _, p := m.find(key, hash); p != nil {
+		(*p).value = value
+		return
+	}
+	t := m.tables[0]
+	if m.rehashing() {
+		t = m.tables[1]
+	}
+	i := t.index(hash)
+	t.buckets[i] = &entry[K, V]{key, value, hash, t.buckets[i]}
+	t.used++
+
+	if !m.rehashing() && t.used >= len(t.buckets)*maxLoad {
+		m.startRehash(len(t.buckets) * 2)
+	}
+}
+
+// Delete 删除键，返回键是否存在
+func (m *HashMap[K, V]) Delete(key K) bool {
+	m.rehashStep(1)
+	t, p := m.find(key, m.hasher(m.seed, key))
+	if p == nil {
+		return false
+	}
+	*p = (*p).next
+	t.used--
+
+	if t = m.tables[0]; !m.rehashing() && len(t.buckets) > minBuckets && t.used*minLoad < len(t.buckets) {
+		m.startRehash(max(minBuckets, 1<<bits.Len(uint(t.used*2))))
+	}
+	return true
+}
+
+// Range 遍历所有键值对，fn 返回 false 时停止。
+// 遍历期间暂停 rehash，因此可以在 fn 中删除元素；新插入的元素可能被遍历到，也可能不会
+func (m *HashMap[K, V]) Range(fn func(key K, value V) bool) {
+	m.iterating++
+	defer func() { m.iterating-- }()
+	for ti := 0; ti < 2; ti++ {
+		for i := 0; m.tables[ti] != nil && i < len(m.tables[ti].buckets); i++ {
+			for e := m.tables[ti].buckets[i]; e != nil; {
+				next := e.next // fn 可能删除 e
+				if !fn(e.key, e.value) {
+					return
+				}
+				e = next
+			}
+		}
+	}
+}
+
+// Stats 哈希表的内部状态
+type Stats struct {
+	Len           int
+	Buckets       int  // 当前表（rehash 期间为旧表）的桶数
+	RehashBuckets int  // rehash 目标表的桶数，不在 rehash 时为 0
+	LongestChain  int  // 最长冲突链的长度
+	Rehashing     bool // 是否正在 rehash
+}
+
+// Stats 返回哈希表的内部状态
+func (m *HashMap[K, V]) Stats() Stats {
+	s := Stats{Len: m.Len(), Buckets: len(m.tables[0].buckets), Rehashing: m.rehashing()}
+	if s.Rehashing {
+		s.RehashBuckets = len(m.tables[1].buckets)
+	}
+	for _, t := range m.tables {
+		if t == nil {
+			break
+		}
+		for _, e := range t.buckets {
+			n := 0
+			for ; e != nil; e = e.next {
+				n++
+			}
+			s.LongestChain = max(s.LongestChain, n)
+		}
+	}
+	return s
+}
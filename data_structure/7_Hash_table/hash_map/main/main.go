@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/rand"
+	"time"
+)
+
+// 哈希表.md 中的哈希函数：各字节之和。与种子无关，字母相同、顺序不同的键全部冲突
+func byteSum(_ maphash.Seed, key string) uint64 {
+	var h uint64
+	for i := 0; i < len(key); i++ {
+		h += uint64(key[i])
+	}
+	return h
+}
+
+// 生成 s 的全排列，它们的字节之和都相同
+func permutations(s []byte, k int, out *[]string) {
+	if k == len(s) {
+		*out = append(*out, string(s))
+		return
+	}
+	for i := k; i < len(s); i++ {
+		s[k], s[i] = s[i], s[k]
+		permutations(s, k+1, out)
+		s[k], s[i] = s[i], s[k]
+	}
+}
+
+func main() {
+	m := NewHashMap[string, string]()
+	m.Put("hello", "world")
+	m.Put("foo", "bar")
+	m.Put("foo", "baz") // 重复的键只更新值
+	v, _ := m.Get("foo")
+	fmt.Println(v, m.Len())                                // baz 2
+	fmt.Println(m.Delete("foo"), m.Delete("foo"), m.Len()) // true false 1
+
+	// 渐进式 rehash：扩容后旧表的桶在后续操作中逐个搬迁
+	ints := NewHashMap[int, int]()
+	for i := 0; i < 12; i++ {
+		ints.Put(i, i*i)
+		fmt.Printf("%+v\n", ints.Stats())
+	}
+	for i := 0; i < 12; i++ {
+		ints.Delete(i)
+	}
+	fmt.Printf("%+v\n", ints.Stats()) // 元素删完后缩回 minBuckets
+
+	// 遍历时删除元素
+	for i := 0; i < 100; i++ {
+		ints.Put(i, i)
+	}
+	ints.Range(func(k, _ int) bool {
+		if k%2 == 1 {
+			ints.Delete(k)
+		}
+		return true
+	})
+	sum := 0
+	ints.Range(func(k, _ int) bool {
+		sum += k
+		return true
+	})
+	fmt.Println(ints.Len(), sum) // 50 2450
+
+	// 与内置 map 对照的随机测试
+	want := map[int]int{}
+	got := NewHashMap[int, int]()
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200000; i++ {
+		k := rng.Intn(5000)
+		switch rng.Intn(3) {
+		case 0:
+			got.Put(k, i)
+			want[k] = i
+		case 1:
+			_, ok := want[k]
+			if got.Delete(k) != ok {
+				panic("Delete mismatch")
+			}
+			delete(want, k)
+		case 2:
+			v, ok := got.Get(k)
+			if w, wok := want[k]; ok != wok || v != w {
+				panic("Get mismatch")
+			}
+		}
+		if got.Len() != len(want) {
+			panic("Len mismatch")
+		}
+	}
+	n := 0
+	got.Range(func(k, v int) bool {
+		if want[k] != v {
+			panic("Range mismatch")
+		}
+		n++
+		return true
+	})
+	fmt.Println("随机测试通过", n == len(want))
+
+	// 哈希泛洪：构造大量在字节和哈希下冲突的键
+	var keys []string
+	permutations([]byte("abcdefg"), 0, &keys)
+	for _, h := range []struct {
+		name   string
+		hasher Hasher[string]
+	}{
+		{"字节和", byteSum},
+		{"maphash", maphash.String},
+	} {
+		start := time.Now()
+		flood := NewHashMapFunc[string, int](h.hasher)
+		for i, k := range keys {
+			flood.Put(k, i)
+		}
+		s := flood.Stats()
+		fmt.Printf("%s: %d 个键，最长冲突链 %d，耗时 %v\n", h.name, s.Len, s.LongestChain, time.Since(start))
+	}
+}
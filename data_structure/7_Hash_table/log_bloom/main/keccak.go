@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Keccak-256：以太坊使用的哈希函数。它与标准化后的 SHA3-256 使用相同的置换 Keccak-f[1600]，
+// 区别只在填充：以太坊沿用了标准化之前的 Keccak，填充字节是 0x01，而 SHA3 是 0x06，
+// 所以两者对同一输入的结果不同。
+//
+// 海绵结构：状态是 5×5 个 64 位的字（1600 位），每次把 rate = 136 字节的输入异或进状态的前部，
+// 然后做一次置换；输入吸收完后从状态前部取出 32 字节作为结果。
+
+const keccakRate = 136 // (1600 - 2·256) / 8
+
+// 24 轮的轮常数，用于 ι 步骤
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// ρ 步骤中 a[x+5y] 的循环左移位数
+var rotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccakF1600 对状态做 24 轮置换，a[x+5y] 是第 (x, y) 个字
+func keccakF1600(a *[25]uint64) {
+	var c, d [5]uint64
+	var b [25]uint64
+	for round := 0; round < 24; round++ {
+		// θ：每个字异或上相邻两列的奇偶校验
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d[x] = c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+		}
+		for i := range a {
+			a[i] ^= d[i%5]
+		}
+		// ρ 和 π：每个字循环移位后换到 (y, 2x+3y) 的位置
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], rotations[x+5*y])
+			}
+		}
+		// χ：行内唯一的非线性步骤
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[y+x] = b[y+x] ^ (^b[y+(x+1)%5] & b[y+(x+2)%5])
+			}
+		}
+		// ι
+		a[0] ^= roundConstants[round]
+	}
+}
+
+// Keccak256 计算若干段数据拼接后的 Keccak-256
+func Keccak256(data ...[]byte) [32]byte {
+	var a [25]uint64
+	var block [keccakRate]byte
+	n := 0 // block 中已有的字节数
+	absorb := func() {
+		for i := 0; i < keccakRate/8; i++ {
+			a[i] ^= binary.LittleEndian.Uint64(block[8*i:])
+		}
+		keccakF1600(&a)
+		n = 0
+	}
+	for _, d := range data {
+		for len(d) > 0 {
+			k := copy(block[n:], d)
+			n += k
+			d = d[k:]
+			if n == keccakRate {
+				absorb()
+			}
+		}
+	}
+	// 填充 pad10*1：第一个填充字节为 0x01，块的最后一个字节最高位置 1，两者可能是同一个字节
+	clear(block[n:])
+	block[n] = 0x01
+	block[keccakRate-1] |= 0x80
+	absorb()
+
+	var out [32]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(out[8*i:], a[i])
+	}
+	return out
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// 以太坊的日志布隆过滤器（黄皮书中的 M3:2048）：每个收据带一个 2048 位的布隆过滤器，
+// 记录其中所有日志的合约地址和每个主题（topic）；区块头中的 logsBloom 是区块内所有收据的布隆过滤器的按位或。
+// 查询某个合约或事件的日志时（eth_getLogs），先用区块头的过滤器跳过一定不相关的区块，只对可能相关的区块读取收据。
+//
+// 每个元素取 Keccak-256 哈希的前 6 个字节，分成 3 对，每对的低 11 位是一个位下标，把对应位置 1。
+// 过滤器按大端序存放：下标为 i 的位在第 255 - i/8 个字节的第 i%8 位。
+// 参数固定为 m = 2048、k = 3，不随元素个数调整，所以日志很多的区块误判率很高。
+
+const (
+	BloomByteLength = 256
+	BloomBitLength  = 8 * BloomByteLength
+)
+
+var errBloomLength = errors.New("logs bloom must be 256 bytes")
+
+// Bloom 2048 位的日志布隆过滤器，零值为空
+type Bloom [BloomByteLength]byte
+
+// 元素对应的 3 个位：字节下标和该字节中的位
+func bloomBits(data []byte) (idx [3]int, mask [3]byte) {
+	h := Keccak256(data)
+	for i := 0; i < 3; i++ {
+		bit := (int(h[2*i])<<8 | int(h[2*i+1])) & (BloomBitLength - 1)
+		idx[i] = BloomByteLength - 1 - bit/8
+		mask[i] = 1 << (bit % 8)
+	}
+	return idx, mask
+}
+
+// Add 加入一个元素，日志中的元素是 20 字节的地址或 32 字节的主题
+func (b *Bloom) Add(data []byte) {
+	idx, mask := bloomBits(data)
+	for i := range idx {
+		b[idx[i]] |= mask[i]
+	}
+}
+
+// Test 判断元素是否可能被加入过；返回 false 时一定没有
+func (b *Bloom) Test(data []byte) bool {
+	idx, mask := bloomBits(data)
+	for i := range idx {
+		if b[idx[i]]&mask[i] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Or 把 other 并入 b，结果等于两者元素之和的过滤器
+func (b *Bloom) Or(other *Bloom) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+// Contains 判断 other 中置 1 的位在 b 中是否都置 1，例如区块的过滤器包含其中每个收据的过滤器
+func (b *Bloom) Contains(other *Bloom) bool {
+	for i := range b {
+		if b[i]&other[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回 JSON-RPC 中使用的 0x 开头的十六进制形式
+func (b Bloom) String() string {
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+// ParseBloom 解析 0x 开头的十六进制形式
+func ParseBloom(s string) (Bloom, error) {
+	var b Bloom
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return b, err
+	}
+	if len(raw) != BloomByteLength {
+		return b, errBloomLength
+	}
+	copy(b[:], raw)
+	return b, nil
+}
+
+// Log 收据中的一条日志，布隆过滤器只用到地址和主题，不包含 data
+type Log struct {
+	Address [20]byte
+	Topics  [][32]byte
+}
+
+// ReceiptBloom 计算收据的布隆过滤器：加入每条日志的地址和所有主题
+func ReceiptBloom(logs []Log) Bloom {
+	var b Bloom
+	for _, l := range logs {
+		b.Add(l.Address[:])
+		for _, t := range l.Topics {
+			b.Add(t[:])
+		}
+	}
+	return b
+}
+
+// HeaderBloom 计算区块头的布隆过滤器：区块内所有收据的布隆过滤器的按位或
+func HeaderBloom(receipts []Bloom) Bloom {
+	var b Bloom
+	for i := range receipts {
+		b.Or(&receipts[i])
+	}
+	return b
+}
+
+// MayContainLog 判断过滤器是否可能包含来自 address、且带有全部 topics 的日志，
+// address 为 nil 时不限合约，eth_getLogs 用它跳过区块
+func (b *Bloom) MayContainLog(address []byte, topics ...[]byte) bool {
+	if address != nil && !b.Test(address) {
+		return false
+	}
+	for _, t := range topics {
+		if !b.Test(t) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"os"
+	"strings"
+)
+
+// testdata/vectors.json 中的测试向量，来源写在文件的 note 和每条收据的 source 字段中
+type vectors struct {
+	Keccak256 []struct {
+		Input string `json:"input"`
+		Hash  string `json:"hash"`
+	} `json:"keccak256"`
+	Strings struct {
+		Positive []string `json:"positive"`
+		Negative []string `json:"negative"`
+	} `json:"strings"`
+	Extensive struct {
+		Format         string `json:"format"`
+		Count          int    `json:"count"`
+		BloomKeccak256 string `json:"bloomKeccak256"`
+	} `json:"extensive"`
+	Receipts []struct {
+		Source string `json:"source"`
+		Block  uint64 `json:"blockNumber"`
+		Logs   []struct {
+			Address string   `json:"address"`
+			Topics  []string `json:"topics"`
+		} `json:"logs"`
+		LogsBloom string `json:"logsBloom"`
+	} `json:"receipts"`
+	AggregateLogsBloom string `json:"aggregateLogsBloom"`
+}
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// 置 1 的位数
+func popCount(b *Bloom) int {
+	n := 0
+	for _, v := range b {
+		n += bits.OnesCount8(v)
+	}
+	return n
+}
+
+func check(v *vectors) error {
+	for _, kv := range v.Keccak256 {
+		if h := Keccak256([]byte(kv.Input)); "0x"+hex.EncodeToString(h[:]) != kv.Hash {
+			return fmt.Errorf("keccak256(%q) = %x, want %s", kv.Input, h, kv.Hash)
+		}
+	}
+	// 跨越多个块、恰好填满一块时的填充
+	for _, n := range []int{keccakRate - 1, keccakRate, keccakRate + 1, 3 * keccakRate} {
+		data := make([]byte, n)
+		if Keccak256(data) != Keccak256(data[:n/2], nil, data[n/2:]) {
+			return fmt.Errorf("keccak256 of %d bytes depends on how the input is split", n)
+		}
+	}
+
+	var b Bloom
+	for _, s := range v.Strings.Positive {
+		b.Add([]byte(s))
+	}
+	for _, s := range v.Strings.Positive {
+		if !b.Test([]byte(s)) {
+			return fmt.Errorf("%q added but not found", s)
+		}
+	}
+	for _, s := range v.Strings.Negative {
+		if b.Test([]byte(s)) {
+			return fmt.Errorf("%q not added but found", s)
+		}
+	}
+
+	b = Bloom{}
+	for i := 0; i < v.Extensive.Count; i++ {
+		b.Add([]byte(fmt.Sprintf(v.Extensive.Format, i)))
+	}
+	if h := Keccak256(b[:]); "0x"+hex.EncodeToString(h[:]) != v.Extensive.BloomKeccak256 {
+		return fmt.Errorf("keccak256 of bloom = %x, want %s", h, v.Extensive.BloomKeccak256)
+	}
+
+	receipts := make([]Bloom, len(v.Receipts))
+	for i, r := range v.Receipts {
+		logs := make([]Log, len(r.Logs))
+		for j, l := range r.Logs {
+			copy(logs[j].Address[:], unhex(l.Address))
+			for _, t := range l.Topics {
+				logs[j].Topics = append(logs[j].Topics, [32]byte(unhex(t)))
+			}
+		}
+		receipts[i] = ReceiptBloom(logs)
+		if receipts[i].String() != r.LogsBloom {
+			return fmt.Errorf("%s: receipt bloom mismatch", r.Source)
+		}
+		for _, l := range logs {
+			if !receipts[i].MayContainLog(l.Address[:], topicSlices(l.Topics)...) {
+				return fmt.Errorf("%s: log %x not found", r.Source, l.Address)
+			}
+		}
+	}
+
+	header := HeaderBloom(receipts)
+	want, err := ParseBloom(v.AggregateLogsBloom)
+	if err != nil {
+		return err
+	}
+	if header != want {
+		return fmt.Errorf("header bloom = %v, want %v", header, want)
+	}
+	for i := range receipts {
+		if !header.Contains(&receipts[i]) {
+			return fmt.Errorf("header bloom does not contain receipt %d", i)
+		}
+	}
+	if _, err := ParseBloom("0x00"); err == nil {
+		return fmt.Errorf("short bloom parsed without error")
+	}
+	return nil
+}
+
+func topicSlices(topics [][32]byte) [][]byte {
+	out := make([][]byte, len(topics))
+	for i := range topics {
+		out[i] = topics[i][:]
+	}
+	return out
+}
+
+func main() {
+	raw, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var v vectors
+	if err := json.Unmarshal(raw, &v); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := check(&v); err != nil {
+		fmt.Println("检查失败：", err)
+		return
+	}
+	fmt.Printf("测试向量全部通过：%d 个 Keccak-256，%d 个收据\n", len(v.Keccak256), len(v.Receipts))
+
+	// 每个收据置 1 的位数，以及按 (置 1 比例)^3 估计的误判率
+	for _, r := range v.Receipts {
+		b, _ := ParseBloom(r.LogsBloom)
+		n := popCount(&b)
+		name := r.Source[strings.LastIndex(r.Source, "/")+1:]
+		fmt.Printf("区块 %-8d %-42s 日志 %2d 条，置 1 的位 %3d，误判率约 %.2e\n",
+			r.Block, name, len(r.Logs), n, math.Pow(float64(n)/BloomBitLength, 3))
+	}
+
+	// 用区块头的过滤器筛选 ERC-20 的 Transfer 事件
+	header, _ := ParseBloom(v.AggregateLogsBloom)
+	transfer := Keccak256([]byte("Transfer(address,address,uint256)"))
+	approval := Keccak256([]byte("Approval(address,address,uint256)"))
+	fmt.Println("汇总过滤器可能包含 Transfer：", header.MayContainLog(nil, transfer[:]))
+	fmt.Println("汇总过滤器可能包含 Approval：", header.MayContainLog(nil, approval[:]))
+
+	// 位数和哈希个数固定，元素越多误判率越高
+	for _, n := range []int{10, 100, 300, 1000} {
+		var b Bloom
+		for i := 0; i < n; i++ {
+			b.Add([]byte(fmt.Sprintf("member %d", i)))
+		}
+		fp := 0
+		const trials = 10000
+		for i := 0; i < trials; i++ {
+			if b.Test([]byte(fmt.Sprintf("other %d", i))) {
+				fp++
+			}
+		}
+		theory := math.Pow(1-math.Exp(-3*float64(n)/BloomBitLength), 3)
+		fmt.Printf("%4d 个元素：误判率 %.4f（理论约 %.4f）\n", n, float64(fp)/trials, theory)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"math"
+	"math/bits"
+)
+
+// 二元熔断过滤器（binary fuse filter, Graf & Lemire, 2022）：XOR 过滤器的改进。
+// 数组被分成许多长度为 2 的幂的小段，每个键的三个位置落在连续的三段中，
+// 这种「空间耦合」的超图在装载率高得多时仍能剥离成功，数组只需键数的约 1.13 倍，
+// 8 位指纹时每个键约 9 位。构建和查询方法与 XOR 过滤器相同。
+
+type BinaryFuseFilter[F fingerprint] struct {
+	seed               uint64
+	segmentLength      uint32
+	segmentLengthMask  uint32
+	segmentCountLength uint32
+	fingerprints       []F
+}
+
+// BuildBinaryFuseFilter 由键集合构建二元熔断过滤器，重复的键只算一次
+func BuildBinaryFuseFilter[F fingerprint](keys [][]byte) (*BinaryFuseFilter[F], error) {
+	hashes := hashKeys(keys)
+	n := len(hashes)
+	// 参数取自论文中元数为 3 时的经验公式
+	segmentLength := uint32(1) << 2
+	if n > 1 {
+		segmentLength = uint32(1) << int(math.Floor(math.Log(float64(n))/math.Log(3.33)+2.25))
+	}
+	segmentLength = min(segmentLength, 1<<18)
+	sizeFactor := 2.0
+	if n > 1 {
+		sizeFactor = max(1.125, 0.875+0.25*math.Log(1e6)/math.Log(float64(n)))
+	}
+	capacity := uint32(math.Round(float64(n) * sizeFactor))
+	segmentCount := max((capacity+segmentLength-1)/segmentLength, 3) - 2
+	f := &BinaryFuseFilter[F]{
+		segmentLength:      segmentLength,
+		segmentLengthMask:  segmentLength - 1,
+		segmentCountLength: segmentCount * segmentLength,
+	}
+	size := int((segmentCount + 2) * segmentLength)
+	for attempt := 0; attempt < maxBuildAttempts; attempt++ {
+		f.seed = mix(uint64(attempt) + 0x9e3779b97f4a7c15)
+		seeded := make([]uint64, len(hashes))
+		for i, h := range hashes {
+			seeded[i] = mix(h ^ f.seed)
+		}
+		order, slots, ok := peel(seeded, size, f.positions)
+		if !ok {
+			continue
+		}
+		f.fingerprints = make([]F, size)
+		assign(f.fingerprints, order, slots, f.positions)
+		return f, nil
+	}
+	return nil, errBuildFailed
+}
+
+// 第一个位置在前 segmentCount 段中，另两个位置分别在其后的两段中
+func (f *BinaryFuseFilter[F]) positions(h uint64) [3]uint32 {
+	hi, _ := bits.Mul64(h, uint64(f.segmentCountLength))
+	h0 := uint32(hi)
+	h1 := h0 + f.segmentLength
+	h2 := h1 + f.segmentLength
+	h1 ^= uint32(h>>18) & f.segmentLengthMask
+	h2 ^= uint32(h) & f.segmentLengthMask
+	return [3]uint32{h0, h1, h2}
+}
+
+func (f *BinaryFuseFilter[F]) Contains(data []byte) bool {
+	h := mix(hash64(data) ^ f.seed)
+	p := f.positions(h)
+	return fingerprintOf[F](h) == f.fingerprints[p[0]]^f.fingerprints[p[1]]^f.fingerprints[p[2]]
+}
+
+func (f *BinaryFuseFilter[F]) SizeInBytes() int {
+	return len(f.fingerprints) * fingerprintBits[F]() / 8
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// 布谷鸟过滤器（Fan 等, 2014）：结构与布谷鸟哈希表相同，但每个槽只存键的指纹。
+// 键的两个候选桶为 i1 = hash 和 i2 = i1 ^ hash(指纹)（部分键布谷鸟哈希），
+// 只凭槽中的指纹和所在桶就能算出另一个候选桶，所以踢出元素时不需要原始键。
+// 与布隆过滤器相比支持删除，在误判率低于约 3% 时也更省空间。
+// 只能删除确实插入过的键；同一个键最多插入 2·bucketSize 次。
+
+const (
+	bucketSize = 4
+	maxKicks   = 500
+)
+
+var errFilterFull = errors.New("cuckoo filter is full")
+
+type CuckooFilter[F fingerprint] struct {
+	buckets [][bucketSize]F // 指纹 0 表示空槽
+	mask    uint64
+	count   int
+	// 踢出循环失败时无处安放的指纹暂存在这里，之后的插入直接返回错误，保证不会漏判
+	victim      F
+	victimIndex uint64
+	hasVictim   bool
+	rng         *rand.Rand
+}
+
+// NewCuckooFilter 创建能容纳约 capacity 个键的布谷鸟过滤器，装载率上限约 95%
+func NewCuckooFilter[F fingerprint](capacity int) *CuckooFilter[F] {
+	n := uint64(1)
+	for n*bucketSize*95 < uint64(capacity)*100 {
+		n <<= 1
+	}
+	return &CuckooFilter[F]{
+		buckets: make([][bucketSize]F, n),
+		mask:    n - 1,
+		rng:     rand.New(rand.NewSource(1)),
+	}
+}
+
+// 键的指纹和第一个候选桶，指纹不能为 0
+func (f *CuckooFilter[F]) index(data []byte) (F, uint64) {
+	h := hash64(data)
+	fp := F(h >> 32)
+	if fp == 0 {
+		fp = 1
+	}
+	return fp, h & f.mask
+}
+
+// 另一个候选桶
+func (f *CuckooFilter[F]) altIndex(i uint64, fp F) uint64 {
+	return (i ^ mix(uint64(fp))) & f.mask
+}
+
+func (f *CuckooFilter[F]) put(i uint64, fp F) bool {
+	for j, v := range f.buckets[i] {
+		if v == 0 {
+			f.buckets[i][j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// Insert 插入键，过滤器已满时返回错误
+func (f *CuckooFilter[F]) Insert(data []byte) error {
+	if f.hasVictim {
+		return errFilterFull
+	}
+	fp, i1 := f.index(data)
+	i2 := f.altIndex(i1, fp)
+	if f.put(i1, fp) || f.put(i2, fp) {
+		f.count++
+		return nil
+	}
+	i := i1
+	if f.rng.Intn(2) == 1 {
+		i = i2
+	}
+	for kick := 0; kick < maxKicks; kick++ {
+		j := f.rng.Intn(bucketSize)
+		fp, f.buckets[i][j] = f.buckets[i][j], fp
+		i = f.altIndex(i, fp)
+		if f.put(i, fp) {
+			f.count++
+			return nil
+		}
+	}
+	f.victim, f.victimIndex, f.hasVictim = fp, i, true
+	f.count++
+	return nil
+}
+
+func (f *CuckooFilter[F]) Contains(data []byte) bool {
+	fp, i1 := f.index(data)
+	i2 := f.altIndex(i1, fp)
+	for _, v := range f.buckets[i1] {
+		if v == fp {
+			return true
+		}
+	}
+	for _, v := range f.buckets[i2] {
+		if v == fp {
+			return true
+		}
+	}
+	return f.hasVictim && f.victim == fp && (f.victimIndex == i1 || f.victimIndex == i2)
+}
+
+// Delete 删除一次插入的键，找不到指纹时返回 false
+func (f *CuckooFilter[F]) Delete(data []byte) bool {
+	fp, i1 := f.index(data)
+	i2 := f.altIndex(i1, fp)
+	for _, i := range []uint64{i1, i2} {
+		for j, v := range f.buckets[i] {
+			if v == fp {
+				f.buckets[i][j] = 0
+				f.count--
+				f.reinsertVictim()
+				return true
+			}
+		}
+	}
+	if f.hasVictim && f.victim == fp && (f.victimIndex == i1 || f.victimIndex == i2) {
+		f.hasVictim = false
+		f.count--
+		return true
+	}
+	return false
+}
+
+// 腾出空槽后尝试把暂存的指纹放回桶中
+func (f *CuckooFilter[F]) reinsertVictim() {
+	if !f.hasVictim {
+		return
+	}
+	i := f.victimIndex
+	if f.put(i, f.victim) || f.put(f.altIndex(i, f.victim), f.victim) {
+		f.hasVictim = false
+	}
+}
+
+func (f *CuckooFilter[F]) Len() int {
+	return f.count
+}
+
+func (f *CuckooFilter[F]) SizeInBytes() int {
+	return len(f.buckets) * bucketSize * fingerprintBits[F]() / 8
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+const n = 1000000
+
+func key(i int) []byte {
+	return []byte("0x" + strconv.FormatInt(int64(i)*2654435761, 16))
+}
+
+// 经验误判率：用 n 个不在集合中的键查询
+func falsePositiveRate(f ApproxMembership) float64 {
+	fp := 0
+	for i := n; i < 2*n; i++ {
+		if f.Contains(key(i)) {
+			fp++
+		}
+	}
+	return float64(fp) / n
+}
+
+func main() {
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = key(i)
+	}
+
+	// 布谷鸟过滤器的桶数取 2 的幂，n 个键时实际装载率不到一半，所以每个键的位数偏高
+	builders := []struct {
+		name     string
+		bits     int
+		expected float64 // 理论误判率
+		build    func() (ApproxMembership, error)
+	}{
+		{"布谷鸟过滤器", 8, 2 * bucketSize / math.Exp2(8), func() (ApproxMembership, error) { return buildCuckoo[uint8](keys) }},
+		{"布谷鸟过滤器", 16, 2 * bucketSize / math.Exp2(16), func() (ApproxMembership, error) { return buildCuckoo[uint16](keys) }},
+		{"XOR 过滤器", 8, 1 / math.Exp2(8), func() (ApproxMembership, error) { return BuildXorFilter[uint8](keys) }},
+		{"XOR 过滤器", 16, 1 / math.Exp2(16), func() (ApproxMembership, error) { return BuildXorFilter[uint16](keys) }},
+		{"二元熔断过滤器", 8, 1 / math.Exp2(8), func() (ApproxMembership, error) { return BuildBinaryFuseFilter[uint8](keys) }},
+		{"二元熔断过滤器", 16, 1 / math.Exp2(16), func() (ApproxMembership, error) { return BuildBinaryFuseFilter[uint16](keys) }},
+	}
+	for _, b := range builders {
+		start := time.Now()
+		f, err := b.build()
+		if err != nil {
+			fmt.Println(b.name, err)
+			return
+		}
+		elapsed := time.Since(start)
+		for _, k := range keys {
+			if !f.Contains(k) {
+				fmt.Println(b.name, "漏判", string(k))
+				return
+			}
+		}
+		fmt.Printf("%s（%d 位指纹）：误判率 %.4f%%（理论约 %.4f%%），每个键 %.2f 位，构建 %v\n",
+			b.name, b.bits, falsePositiveRate(f)*100, b.expected*100, float64(f.SizeInBytes())*8/n, elapsed.Round(time.Millisecond))
+	}
+
+	// 布谷鸟过滤器支持删除
+	c := NewCuckooFilter[uint16](1000)
+	for i := 0; i < 1000; i++ {
+		c.Insert(key(i))
+	}
+	for i := 0; i < 1000; i += 2 {
+		c.Delete(key(i))
+	}
+	remaining := 0
+	for i := 0; i < 1000; i++ {
+		if c.Contains(key(i)) {
+			remaining++
+		}
+	}
+	fmt.Println("删除一半后：", c.Len(), remaining)
+
+	// 装满后插入失败
+	full := NewCuckooFilter[uint16](100)
+	var err error
+	inserted := 0
+	for ; err == nil; inserted++ {
+		err = full.Insert(key(inserted))
+	}
+	fmt.Printf("容量 %d 的布谷鸟过滤器插入 %d 个键后：%v\n", 100, inserted-1, err)
+}
+
+func buildCuckoo[F fingerprint](keys [][]byte) (*CuckooFilter[F], error) {
+	f := NewCuckooFilter[F](len(keys))
+	for _, k := range keys {
+		if err := f.Insert(k); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
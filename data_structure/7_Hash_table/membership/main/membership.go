@@ -0,0 +1,47 @@
+package main
+
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
+// 近似成员查询（approximate membership）：与布隆过滤器一样，可能误判存在，但不会漏判。
+// 本目录的三种过滤器都只存放键的短指纹（fingerprint），指纹位数决定误判率，约为 2^-位数 的常数倍。
+
+// ApproxMembership 各种近似成员过滤器共同的接口
+type ApproxMembership interface {
+	// Contains 判断数据是否可能在集合中；返回 false 时一定不在
+	Contains(data []byte) bool
+	// SizeInBytes 返回过滤器占用的内存
+	SizeInBytes() int
+}
+
+// fingerprint 指纹类型，8 位误判率约 0.4%，16 位约 0.0015%
+type fingerprint interface {
+	~uint8 | ~uint16 | ~uint32
+}
+
+func fingerprintBits[F fingerprint]() int {
+	return bits.OnesCount64(uint64(^F(0)))
+}
+
+// 键的 64 位哈希，指纹和位置都由它导出。XOR 和二元融合过滤器构造失败时，换一个 seed 与它混合后重试
+func hash64(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return mix(h.Sum64())
+}
+
+// splitmix64 的终结函数，是双射，不同的输入得到不同的输出
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func fingerprintOf[F fingerprint](h uint64) F {
+	return F(h ^ h>>32)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"slices"
+)
+
+// XOR 过滤器（Graf & Lemire, 2019）：静态过滤器，由确定的键集合一次构建，之后不能增删。
+// 每个键映射到数组 B 中的三个位置，构建时为 B 赋值使得 B[h0] ^ B[h1] ^ B[h2] 等于键的指纹；
+// 查询时比较这三个位置的异或与指纹。数组长度约为键数的 1.23 倍，8 位指纹时每个键约 9.84 位。
+//
+// 赋值方法是「剥离」（peeling）：反复找只被一个键占用的位置，把这个键压栈并从三个位置上移除，
+// 直到所有键都被剥离，再按出栈顺序给每个键占用的那个位置赋值，保证赋值时另两个位置不再改变。
+// 三个位置构成的超图中有环时剥离会失败，此时换一个种子重来，期望只需很少几次。
+
+const maxBuildAttempts = 100
+
+var errBuildFailed = errors.New("failed to build filter: too many attempts, are there duplicate keys?")
+
+// 去重后的键哈希值
+func hashKeys(keys [][]byte) []uint64 {
+	hashes := make([]uint64, len(keys))
+	for i, k := range keys {
+		hashes[i] = hash64(k)
+	}
+	slices.Sort(hashes)
+	return slices.Compact(hashes)
+}
+
+// peel 按 positions 给出的三个位置剥离所有哈希值，返回剥离顺序（哈希值与它独占的位置），失败时返回 false
+func peel(hashes []uint64, size int, positions func(h uint64) [3]uint32) ([]uint64, []uint32, bool) {
+	count := make([]uint8, size)
+	xorHash := make([]uint64, size) // 占用该位置的所有哈希值的异或，count 为 1 时就是唯一那个哈希值
+	for _, h := range hashes {
+		for _, p := range positions(h) {
+			count[p]++
+			xorHash[p] ^= h
+		}
+	}
+	queue := make([]uint32, 0, size)
+	for i, c := range count {
+		if c == 1 {
+			queue = append(queue, uint32(i))
+		}
+	}
+	order := make([]uint64, 0, len(hashes))
+	slots := make([]uint32, 0, len(hashes))
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if count[i] != 1 {
+			continue
+		}
+		h := xorHash[i]
+		order = append(order, h)
+		slots = append(slots, i)
+		for _, p := range positions(h) {
+			count[p]--
+			xorHash[p] ^= h
+			if count[p] == 1 {
+				queue = append(queue, p)
+			}
+		}
+	}
+	return order, slots, len(order) == len(hashes)
+}
+
+// assign 按剥离的逆序给独占的位置赋值
+func assign[F fingerprint](b []F, order []uint64, slots []uint32, positions func(h uint64) [3]uint32) {
+	for i := len(order) - 1; i >= 0; i-- {
+		h, slot := order[i], slots[i]
+		ps := positions(h)
+		b[slot] = 0
+		b[slot] = fingerprintOf[F](h) ^ b[ps[0]] ^ b[ps[1]] ^ b[ps[2]]
+	}
+}
+
+type XorFilter[F fingerprint] struct {
+	seed          uint64
+	segmentLength uint32
+	fingerprints  []F
+}
+
+// BuildXorFilter 由键集合构建 XOR 过滤器，重复的键只算一次
+func BuildXorFilter[F fingerprint](keys [][]byte) (*XorFilter[F], error) {
+	hashes := hashKeys(keys)
+	capacity := 32 + len(hashes)*123/100
+	f := &XorFilter[F]{segmentLength: uint32(capacity/3 + 1)}
+	size := int(f.segmentLength) * 3
+	for attempt := 0; attempt < maxBuildAttempts; attempt++ {
+		f.seed = mix(uint64(attempt) + 0x9e3779b97f4a7c15)
+		seeded := make([]uint64, len(hashes))
+		for i, h := range hashes {
+			seeded[i] = mix(h ^ f.seed)
+		}
+		order, slots, ok := peel(seeded, size, f.positions)
+		if !ok {
+			continue
+		}
+		f.fingerprints = make([]F, size)
+		assign(f.fingerprints, order, slots, f.positions)
+		return f, nil
+	}
+	return nil, errBuildFailed
+}
+
+// 三个位置分别位于三段中，互不相同
+func (f *XorFilter[F]) positions(h uint64) [3]uint32 {
+	l := uint64(f.segmentLength)
+	return [3]uint32{
+		uint32(reduce(h, l)),
+		uint32(l + reduce(h<<21|h>>43, l)),
+		uint32(2*l + reduce(h<<42|h>>22, l)),
+	}
+}
+
+// reduce 把 32 位哈希值均匀映射到 [0, n)，用乘法代替取模（Lemire）
+func reduce(h, n uint64) uint64 {
+	return (h & 0xffffffff) * n >> 32
+}
+
+func (f *XorFilter[F]) Contains(data []byte) bool {
+	h := mix(hash64(data) ^ f.seed)
+	p := f.positions(h)
+	return fingerprintOf[F](h) == f.fingerprints[p[0]]^f.fingerprints[p[1]]^f.fingerprints[p[2]]
+}
+
+func (f *XorFilter[F]) SizeInBytes() int {
+	return len(f.fingerprints) * fingerprintBits[F]() / 8
+}
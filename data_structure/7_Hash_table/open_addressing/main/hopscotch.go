@@ -0,0 +1,151 @@
+package main
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// Hopscotch 哈希：保证每个元素都位于理想槽位之后 hopRange 个槽以内（邻域）。
+// 每个槽有一个位图 hop，第 i 位为 1 表示槽 home+i 中的元素以本槽为理想槽位，
+// 查找只需检查位图中为 1 的几个槽。插入时先线性探测找到一个空槽，如果空槽不在邻域内，
+// 就在空槽之前的 hopRange-1 个槽中找一个可以合法后移到空槽的元素与空槽交换，
+// 让空槽一步步「跳」回邻域。找不到可以移动的元素时扩容。
+
+const hopRange = 32
+
+type hsSlot[K comparable, V any] struct {
+	key   K
+	value V
+	hop   uint32 // 以本槽为理想槽位的元素分布
+	full  bool
+}
+
+type Hopscotch[K comparable, V any] struct {
+	seed   maphash.Seed
+	slots  []hsSlot[K, V]
+	length int
+}
+
+func NewHopscotch[K comparable, V any]() *Hopscotch[K, V] {
+	return &Hopscotch[K, V]{seed: maphash.MakeSeed(), slots: make([]hsSlot[K, V], minCapacity)}
+}
+
+func (m *Hopscotch[K, V]) Len() int {
+	return m.length
+}
+
+func (m *Hopscotch[K, V]) home(key K) int {
+	return int(maphash.Comparable(m.seed, key)) & (len(m.slots) - 1)
+}
+
+// 返回键所在的槽及其理想槽位，不存在时返回 -1
+func (m *Hopscotch[K, V]) find(key K) (int, int) {
+	mask := len(m.slots) - 1
+	home := m.home(key)
+	for hop := m.slots[home].hop; hop != 0; hop &= hop - 1 {
+		i := (home + bits.TrailingZeros32(hop)) & mask
+		if m.slots[i].key == key {
+			return i, home
+		}
+	}
+	return -1, home
+}
+
+func (m *Hopscotch[K, V]) Get(key K) (V, bool) {
+	if i, _ := m.find(key); i >= 0 {
+		return m.slots[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *Hopscotch[K, V]) Put(key K, value V) {
+	if i, _ := m.find(key); i >= 0 {
+		m.slots[i].value = value
+		return
+	}
+	if (m.length+1)*10 > len(m.slots)*9 {
+		m.grow()
+	}
+	for !m.insert(key, value) {
+		m.grow()
+	}
+}
+
+// 插入一个确定不存在的键，邻域内无法腾出空槽时返回 false
+func (m *Hopscotch[K, V]) insert(key K, value V) bool {
+	mask := len(m.slots) - 1
+	home := m.home(key)
+	dist := 0 // 空槽离 home 的距离
+	for ; m.slots[(home+dist)&mask].full; dist++ {
+		if dist == len(m.slots) {
+			return false
+		}
+	}
+	for dist >= hopRange {
+		free := (home + dist) & mask
+		moved := false
+		// 从离空槽最远的候选理想槽位开始找，每次能跳得更远
+		for back := hopRange - 1; back > 0 && !moved; back-- {
+			b := (free - back) & mask
+			for hop := m.slots[b].hop; hop != 0; hop &= hop - 1 {
+				off := bits.TrailingZeros32(hop)
+				if off >= back {
+					break // 这个元素在空槽之后，不能移动
+				}
+				src := (b + off) & mask
+				m.slots[free].key, m.slots[free].value, m.slots[free].full = m.slots[src].key, m.slots[src].value, true
+				m.slots[b].hop = m.slots[b].hop&^(1<<off) | 1<<back
+				m.slots[src] = hsSlot[K, V]{hop: m.slots[src].hop}
+				dist -= back - off
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			return false
+		}
+	}
+	s := &m.slots[(home+dist)&mask]
+	s.key, s.value, s.full = key, value, true
+	m.slots[home].hop |= 1 << dist
+	m.length++
+	return true
+}
+
+func (m *Hopscotch[K, V]) Delete(key K) bool {
+	i, home := m.find(key)
+	if i < 0 {
+		return false
+	}
+	m.slots[home].hop &^= 1 << ((i - home) & (len(m.slots) - 1))
+	m.slots[i] = hsSlot[K, V]{hop: m.slots[i].hop}
+	m.length--
+	return true
+}
+
+func (m *Hopscotch[K, V]) grow() {
+	old := m.slots
+	for size := len(old) * 2; ; size *= 2 {
+		m.slots = make([]hsSlot[K, V], size)
+		m.length = 0
+		ok := true
+		for i := range old {
+			if old[i].full && !m.insert(old[i].key, old[i].value) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return
+		}
+	}
+}
+
+func (m *Hopscotch[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.slots {
+		if m.slots[i].full && !fn(m.slots[i].key, m.slots[i].value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import "hash/maphash"
+
+// 开放寻址法：所有元素都直接存放在槽数组中，冲突时按某种探测序列寻找下一个空槽。
+// 本目录下的几种实现容量都是 2 的幂，哈希函数都使用带随机种子的 maphash。
+// 遍历期间不能修改表。
+
+const minCapacity = 8
+
+// 线性探测：冲突时依次检查下一个槽。
+// 删除不能直接把槽置空，否则会截断其它键的探测序列，因此留下墓碑（tombstone），
+// 查找时跳过墓碑继续探测，插入时复用遇到的第一个墓碑。墓碑同样占用探测长度，
+// 所以扩容条件按「元素 + 墓碑」计算，重建时墓碑被清除。
+
+const (
+	slotEmpty uint8 = iota
+	slotFull
+	slotDeleted
+)
+
+type lpSlot[K comparable, V any] struct {
+	key   K
+	value V
+	state uint8
+}
+
+type LinearProbing[K comparable, V any] struct {
+	seed       maphash.Seed
+	slots      []lpSlot[K, V]
+	length     int
+	tombstones int
+}
+
+func NewLinearProbing[K comparable, V any]() *LinearProbing[K, V] {
+	return &LinearProbing[K, V]{seed: maphash.MakeSeed(), slots: make([]lpSlot[K, V], minCapacity)}
+}
+
+func (m *LinearProbing[K, V]) Len() int {
+	return m.length
+}
+
+func (m *LinearProbing[K, V]) mask() int {
+	return len(m.slots) - 1
+}
+
+// 返回键所在的槽，不存在时返回 -1
+func (m *LinearProbing[K, V]) find(key K) int {
+	mask := m.mask()
+	for i := int(maphash.Comparable(m.seed, key)) & mask; ; i = (i + 1) & mask {
+		s := &m.slots[i]
+		switch {
+		case s.state == slotEmpty:
+			return -1
+		case s.state == slotFull && s.key == key:
+			return i
+		}
+	}
+}
+
+func (m *LinearProbing[K, V]) Get(key K) (V, bool) {
+	if i := m.find(key); i >= 0 {
+		return m.slots[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *LinearProbing[K, V]) Put(key K, value V) {
+	mask := m.mask()
+	insert := -1
+	i := int(maphash.Comparable(m.seed, key)) & mask
+	for ; m.slots[i].state != slotEmpty; i = (i + 1) & mask {
+		s := &m.slots[i]
+		if s.state == slotFull && s.key == key {
+			s.value = value
+			return
+		}
+		if s.state == slotDeleted && insert < 0 {
+			insert = i
+		}
+	}
+	if insert < 0 {
+		insert = i
+	} else {
+		m.tombstones--
+	}
+	m.slots[insert] = lpSlot[K, V]{key, value, slotFull}
+	m.length++
+	if (m.length+m.tombstones)*4 >= len(m.slots)*3 {
+		m.resize()
+	}
+}
+
+func (m *LinearProbing[K, V]) Delete(key K) bool {
+	i := m.find(key)
+	if i < 0 {
+		return false
+	}
+	mask := m.mask()
+	m.slots[i] = lpSlot[K, V]{}
+	if m.slots[(i+1)&mask].state == slotEmpty {
+		// 后面是空槽，不会有探测序列经过这里，连同前面相连的墓碑一起置空
+		for j := (i - 1) & mask; m.slots[j].state == slotDeleted; j = (j - 1) & mask {
+			m.slots[j].state = slotEmpty
+			m.tombstones--
+		}
+	} else {
+		m.slots[i].state = slotDeleted
+		m.tombstones++
+	}
+	m.length--
+	return true
+}
+
+// 元素超过容量一半时扩容为两倍，否则只是原地重建以清除墓碑
+func (m *LinearProbing[K, V]) resize() {
+	size := len(m.slots)
+	if m.length*2 >= size {
+		size *= 2
+	}
+	old := m.slots
+	m.slots = make([]lpSlot[K, V], size)
+	m.length, m.tombstones = 0, 0
+	for i := range old {
+		if old[i].state == slotFull {
+			m.Put(old[i].key, old[i].value)
+		}
+	}
+}
+
+func (m *LinearProbing[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.slots {
+		if m.slots[i].state == slotFull && !fn(m.slots[i].key, m.slots[i].value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// 内置 map 的包装，作为基准测试的参照
+type builtinMap[K comparable, V any] map[K]V
+
+func (m builtinMap[K, V]) Put(key K, value V) {
+	m[key] = value
+}
+
+func (m builtinMap[K, V]) Get(key K) (V, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m builtinMap[K, V]) Delete(key K) bool {
+	_, ok := m[key]
+	delete(m, key)
+	return ok
+}
+
+func (m builtinMap[K, V]) Len() int {
+	return len(m)
+}
+
+func (m builtinMap[K, V]) Range(fn func(key K, value V) bool) {
+	for k, v := range m {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+const benchN = 1 << 16
+
+func filled(newMap func() Map[int, int]) Map[int, int] {
+	m := newMap()
+	for i := 0; i < benchN; i++ {
+		m.Put(i, i)
+	}
+	return m
+}
+
+// 从空表开始插入 benchN 个键，包含扩容的开销
+func benchInsert(newMap func() Map[int, int]) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			filled(newMap)
+		}
+	}
+}
+
+func benchLookupHit(newMap func() Map[int, int]) func(b *testing.B) {
+	return func(b *testing.B) {
+		m := filled(newMap)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m.Get(i & (benchN - 1))
+		}
+	}
+}
+
+func benchLookupMiss(newMap func() Map[int, int]) func(b *testing.B) {
+	return func(b *testing.B) {
+		m := filled(newMap)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m.Get(benchN + i)
+		}
+	}
+}
+
+// 依次删除所有键，删空后停止计时重新填满
+func benchDelete(newMap func() Map[int, int]) func(b *testing.B) {
+	return func(b *testing.B) {
+		m := filled(newMap)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			k := i & (benchN - 1)
+			if k == 0 && i > 0 {
+				b.StopTimer()
+				m = filled(newMap)
+				b.StartTimer()
+			}
+			m.Delete(k)
+		}
+	}
+}
+
+func main() {
+	impls := []struct {
+		name string
+		new  func() Map[int, int]
+	}{
+		{"线性探测", func() Map[int, int] { return NewLinearProbing[int, int]() }},
+		{"Robin Hood", func() Map[int, int] { return NewRobinHood[int, int]() }},
+		{"Hopscotch", func() Map[int, int] { return NewHopscotch[int, int]() }},
+		{"Swiss table", func() Map[int, int] { return NewSwissTable[int, int]() }},
+		{"内置 map", func() Map[int, int] { return builtinMap[int, int]{} }},
+	}
+
+	for _, impl := range impls {
+		if err := conformance(impl.new); err != nil {
+			fmt.Println(impl.name, "一致性检查失败：", err)
+			return
+		}
+		fmt.Println(impl.name, "一致性检查通过")
+	}
+
+	// 字符串键
+	s := NewSwissTable[string, int]()
+	s.Put("hello", 1)
+	s.Put("world", 2)
+	s.Delete("hello")
+	fmt.Println(s.Get("hello"))
+	fmt.Println(s.Get("world"))
+
+	for _, bench := range []struct {
+		name string
+		fn   func(func() Map[int, int]) func(*testing.B)
+	}{
+		{"插入", benchInsert},
+		{"查找命中", benchLookupHit},
+		{"查找未命中", benchLookupMiss},
+		{"删除", benchDelete},
+	} {
+		for _, impl := range impls {
+			r := testing.Benchmark(bench.fn(impl.new))
+			fmt.Printf("%s %s: %s %s\n", bench.name, impl.name, r, r.MemString())
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import "hash/maphash"
+
+// Robin Hood 哈希：线性探测的改进。每个元素记录自己离理想槽位的距离（探测长度），
+// 插入时如果遇到比自己「富有」（距离更短）的元素，就抢占它的槽位，让它继续往后找。
+// 这样各元素的探测长度趋于平均，查找时一旦遇到距离比当前探测长度短的元素，就可以断定键不存在。
+// 删除时把后面距离大于 0 的元素依次前移一格（backward shift），不需要墓碑。
+
+type rhSlot[K comparable, V any] struct {
+	key   K
+	value V
+	dist  int32 // 离理想槽位的距离加 1，0 表示空槽
+}
+
+type RobinHood[K comparable, V any] struct {
+	seed   maphash.Seed
+	slots  []rhSlot[K, V]
+	length int
+}
+
+func NewRobinHood[K comparable, V any]() *RobinHood[K, V] {
+	return &RobinHood[K, V]{seed: maphash.MakeSeed(), slots: make([]rhSlot[K, V], minCapacity)}
+}
+
+func (m *RobinHood[K, V]) Len() int {
+	return m.length
+}
+
+func (m *RobinHood[K, V]) find(key K) int {
+	mask := len(m.slots) - 1
+	i := int(maphash.Comparable(m.seed, key)) & mask
+	for dist := int32(1); ; dist++ {
+		s := &m.slots[i]
+		if s.dist < dist {
+			return -1
+		}
+		if s.dist == dist && s.key == key {
+			return i
+		}
+		i = (i + 1) & mask
+	}
+}
+
+func (m *RobinHood[K, V]) Get(key K) (V, bool) {
+	if i := m.find(key); i >= 0 {
+		return m.slots[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *RobinHood[K, V]) Put(key K, value V) {
+	if i := m.find(key); i >= 0 {
+		m.slots[i].value = value
+		return
+	}
+	if (m.length+1)*8 > len(m.slots)*7 {
+		m.grow()
+	}
+	m.insert(rhSlot[K, V]{key, value, 1})
+}
+
+// 插入一个确定不存在的元素
+func (m *RobinHood[K, V]) insert(cur rhSlot[K, V]) {
+	mask := len(m.slots) - 1
+	i := int(maphash.Comparable(m.seed, cur.key)) & mask
+	for {
+		s := &m.slots[i]
+		if s.dist == 0 {
+			*s = cur
+			m.length++
+			return
+		}
+		if s.dist < cur.dist {
+			*s, cur = cur, *s
+		}
+		cur.dist++
+		i = (i + 1) & mask
+	}
+}
+
+func (m *RobinHood[K, V]) Delete(key K) bool {
+	i := m.find(key)
+	if i < 0 {
+		return false
+	}
+	mask := len(m.slots) - 1
+	for {
+		next := (i + 1) & mask
+		if m.slots[next].dist <= 1 {
+			break
+		}
+		m.slots[i] = m.slots[next]
+		m.slots[i].dist--
+		i = next
+	}
+	m.slots[i] = rhSlot[K, V]{}
+	m.length--
+	return true
+}
+
+func (m *RobinHood[K, V]) grow() {
+	old := m.slots
+	m.slots = make([]rhSlot[K, V], len(old)*2)
+	m.length = 0
+	for i := range old {
+		if old[i].dist > 0 {
+			m.insert(rhSlot[K, V]{old[i].key, old[i].value, 1})
+		}
+	}
+}
+
+func (m *RobinHood[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.slots {
+		if m.slots[i].dist > 0 && !fn(m.slots[i].key, m.slots[i].value) {
+			return
+		}
+	}
+}
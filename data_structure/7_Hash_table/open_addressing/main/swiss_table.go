@@ -0,0 +1,196 @@
+package main
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// Swiss table 风格的哈希表（abseil 的 flat_hash_map，Go 1.24 起内置 map 也采用这种结构）。
+// 槽按 groupSize 个一组，每组有 groupSize 个控制字节：空槽、墓碑，或者满槽时存放哈希值的低 7 位（h2）。
+// 哈希值的其余部分（h1）决定从哪一组开始探测，组之间按三角数序列跳跃。
+// 查找时先把整组控制字节当作一个 uint64，用位运算（SWAR，不需要 SIMD 指令）一次比较 8 个 h2，
+// 只有 h2 相同的槽才需要比较键；组内只要还有空槽，探测就可以停止。
+
+const groupSize = 8
+
+const (
+	ctrlEmpty   byte = 0b1000_0000
+	ctrlDeleted byte = 0b1111_1110
+)
+
+const (
+	lsbs uint64 = 0x0101010101010101
+	msbs uint64 = 0x8080808080808080
+)
+
+// bitset 每个字节的最高位标记组内对应的槽
+type bitset uint64
+
+func (b bitset) first() int {
+	return bits.TrailingZeros64(uint64(b)) / 8
+}
+
+func (b bitset) removeFirst() bitset {
+	return b & (b - 1)
+}
+
+type group[K comparable, V any] struct {
+	ctrl  uint64 // 第 i 个字节是第 i 个槽的控制字节
+	keys  [groupSize]K
+	value [groupSize]V
+}
+
+// 控制字节等于 h2 的槽。可能有误报（紧跟在匹配字节之后的字节），调用者需要再比较键
+func (g *group[K, V]) match(h2 byte) bitset {
+	x := g.ctrl ^ (lsbs * uint64(h2))
+	return bitset((x - lsbs) &^ x & msbs)
+}
+
+// 空槽：最高位为 1 且第 1 位为 0
+func (g *group[K, V]) matchEmpty() bitset {
+	return bitset(g.ctrl &^ (g.ctrl << 6) & msbs)
+}
+
+// 空槽或墓碑：最高位为 1
+func (g *group[K, V]) matchEmptyOrDeleted() bitset {
+	return bitset(g.ctrl & msbs)
+}
+
+func (g *group[K, V]) setCtrl(i int, c byte) {
+	shift := uint(i) * 8
+	g.ctrl = g.ctrl&^(0xff<<shift) | uint64(c)<<shift
+}
+
+type SwissTable[K comparable, V any] struct {
+	seed       maphash.Seed
+	groups     []group[K, V]
+	length     int
+	tombstones int
+}
+
+func NewSwissTable[K comparable, V any]() *SwissTable[K, V] {
+	m := &SwissTable[K, V]{seed: maphash.MakeSeed()}
+	m.groups = newGroups[K, V](minCapacity / groupSize)
+	return m
+}
+
+func newGroups[K comparable, V any](n int) []group[K, V] {
+	groups := make([]group[K, V], n)
+	for i := range groups {
+		groups[i].ctrl = lsbs * uint64(ctrlEmpty)
+	}
+	return groups
+}
+
+func (m *SwissTable[K, V]) Len() int {
+	return m.length
+}
+
+func (m *SwissTable[K, V]) hash(key K) (uint64, byte) {
+	h := maphash.Comparable(m.seed, key)
+	return h >> 7, byte(h & 0x7f)
+}
+
+// 返回键所在的组和组内下标，不存在时组为 nil
+func (m *SwissTable[K, V]) find(key K) (*group[K, V], int) {
+	h1, h2 := m.hash(key)
+	mask := uint64(len(m.groups) - 1)
+	for pos, step := h1&mask, uint64(1); ; pos, step = (pos+step)&mask, step+1 {
+		g := &m.groups[pos]
+		for b := g.match(h2); b != 0; b = b.removeFirst() {
+			if i := b.first(); g.keys[i] == key {
+				return g, i
+			}
+		}
+		if g.matchEmpty() != 0 {
+			return nil, 0
+		}
+	}
+}
+
+func (m *SwissTable[K, V]) Get(key K) (V, bool) {
+	if g, i := m.find(key); g != nil {
+		return g.value[i], true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *SwissTable[K, V]) Put(key K, value V) {
+	if g, i := m.find(key); g != nil {
+		g.value[i] = value
+		return
+	}
+	if (m.length+m.tombstones+1)*8 > len(m.groups)*groupSize*7 {
+		m.resize()
+	}
+	m.insert(key, value)
+}
+
+// 插入一个确定不存在的键，放在探测序列上第一个空槽或墓碑处
+func (m *SwissTable[K, V]) insert(key K, value V) {
+	h1, h2 := m.hash(key)
+	mask := uint64(len(m.groups) - 1)
+	for pos, step := h1&mask, uint64(1); ; pos, step = (pos+step)&mask, step+1 {
+		g := &m.groups[pos]
+		if b := g.matchEmptyOrDeleted(); b != 0 {
+			i := b.first()
+			if byte(g.ctrl>>(uint(i)*8)) == ctrlDeleted {
+				m.tombstones--
+			}
+			g.keys[i], g.value[i] = key, value
+			g.setCtrl(i, h2)
+			m.length++
+			return
+		}
+	}
+}
+
+func (m *SwissTable[K, V]) Delete(key K) bool {
+	g, i := m.find(key)
+	if g == nil {
+		return false
+	}
+	var zeroK K
+	var zeroV V
+	g.keys[i], g.value[i] = zeroK, zeroV
+	// 组内还有空槽说明探测序列从未越过这一组，可以直接置空
+	if g.matchEmpty() != 0 {
+		g.setCtrl(i, ctrlEmpty)
+	} else {
+		g.setCtrl(i, ctrlDeleted)
+		m.tombstones++
+	}
+	m.length--
+	return true
+}
+
+// 元素超过容量一半时扩容为两倍，否则原地重建以清除墓碑
+func (m *SwissTable[K, V]) resize() {
+	n := len(m.groups)
+	if m.length*2 >= n*groupSize {
+		n *= 2
+	}
+	old := m.groups
+	m.groups = newGroups[K, V](n)
+	m.length, m.tombstones = 0, 0
+	for gi := range old {
+		g := &old[gi]
+		for b := ^g.matchEmptyOrDeleted() & bitset(msbs); b != 0; b = b.removeFirst() {
+			i := b.first()
+			m.insert(g.keys[i], g.value[i])
+		}
+	}
+}
+
+func (m *SwissTable[K, V]) Range(fn func(key K, value V) bool) {
+	for gi := range m.groups {
+		g := &m.groups[gi]
+		for b := ^g.matchEmptyOrDeleted() & bitset(msbs); b != 0; b = b.removeFirst() {
+			i := b.first()
+			if !fn(g.keys[i], g.value[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+const numKeys = 200000
+
+func key(i int) string {
+	return "addr-" + strconv.Itoa(i)
+}
+
+func nodeNames(n int) []string {
+	nodes := make([]string, n)
+	for i := range nodes {
+		nodes[i] = "node-" + strconv.Itoa(i)
+	}
+	return nodes
+}
+
+// 均衡性：各节点分到的键数与平均值的最大相对偏差
+func balance(p Placer, nodes []string) float64 {
+	counts := make(map[string]int)
+	for i := 0; i < numKeys; i++ {
+		n, _ := p.Place(key(i))
+		counts[n]++
+	}
+	avg := float64(numKeys) / float64(len(nodes))
+	worst := 0.0
+	for _, n := range nodes {
+		worst = max(worst, math.Abs(float64(counts[n])-avg)/avg)
+	}
+	return worst
+}
+
+// 扰动：节点从 before 变为 after 时迁移的键的比例，以及迁移的键中有多少并非必须迁移
+// （增加节点时不是移到新节点，删除节点时不是从被删节点移出）
+func disruption(before, after Placer, beforeNodes, afterNodes []string) (moved, unnecessary float64) {
+	m, u := 0, 0
+	for i := 0; i < numKeys; i++ {
+		a, _ := before.Place(key(i))
+		b, _ := after.Place(key(i))
+		if a == b {
+			continue
+		}
+		m++
+		if slices.Contains(beforeNodes, b) && slices.Contains(afterNodes, a) {
+			u++
+		}
+	}
+	return float64(m) / numKeys, float64(u) / numKeys
+}
+
+func main() {
+	algorithms := []struct {
+		name string
+		new  func(nodes []string) Placer
+	}{
+		{"Rendezvous", func(nodes []string) Placer { return NewRendezvous(nodes) }},
+		{"Jump", func(nodes []string) Placer { return NewJump(nodes) }},
+		{"Maglev", func(nodes []string) Placer {
+			m, _ := NewMaglev(nodes, DefaultMaglevSize) // 默认大小是素数
+			return m
+		}},
+	}
+
+	nodes := nodeNames(10)
+	added := nodeNames(11)
+	removedLast := nodeNames(9)
+	removedMiddle := slices.Delete(slices.Clone(nodes), 4, 5)
+
+	const balanceTolerance = 0.05
+	for _, alg := range algorithms {
+		p := alg.new(nodes)
+		fmt.Printf("%s:\n", alg.name)
+		b := balance(p, nodes)
+		fmt.Printf("  均衡性：最大偏差 %.2f%%，%s\n", b*100, verdict(b <= balanceTolerance))
+
+		for _, change := range []struct {
+			name  string
+			nodes []string
+			ideal float64
+		}{
+			{"增加一个节点", added, 1.0 / 11},
+			{"删除最后一个节点", removedLast, 1.0 / 10},
+			{"删除中间的节点", removedMiddle, 1.0 / 10}, // Jump 按下标放置，预期不通过
+		} {
+			moved, unnecessary := disruption(p, alg.new(change.nodes), nodes, change.nodes)
+			// 迁移比例不超过理想值的 1.1 倍，且几乎没有不必要的迁移
+			ok := moved <= change.ideal*1.1 && unnecessary <= 0.01
+			fmt.Printf("  %s：迁移 %.2f%%（理想 %.2f%%），不必要的迁移 %.2f%%，%s\n",
+				change.name, moved*100, change.ideal*100, unnecessary*100, verdict(ok))
+		}
+	}
+
+	// 查找耗时随节点数的变化
+	for _, n := range []int{10, 1000} {
+		nodes := nodeNames(n)
+		for _, alg := range algorithms {
+			p := alg.new(nodes)
+			r := testing.Benchmark(func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					p.Place("0xdeadbeef")
+				}
+			})
+			fmt.Printf("%d 个节点 %s: %s\n", n, alg.name, r)
+		}
+	}
+
+	// 没有节点时返回错误；Maglev 的表大小必须是大于 1 的素数
+	for _, alg := range algorithms {
+		_, err := alg.new(nil).Place("0xdeadbeef")
+		fmt.Printf("%s 没有节点：%v\n", alg.name, err)
+	}
+	for _, size := range []int{1, 65536} {
+		_, err := NewMaglev(nodes, size)
+		fmt.Printf("Maglev 表大小 %d：%v\n", size, err)
+	}
+}
+
+func verdict(ok bool) string {
+	if ok {
+		return "通过"
+	}
+	return "未通过"
+}
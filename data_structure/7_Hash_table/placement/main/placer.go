@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"hash/fnv"
+	"slices"
+	"sort"
+)
+
+// 把键放置到节点上的几种算法，都只依赖节点列表，不需要像一致性哈希环那样维护虚拟节点。
+
+var (
+	errNoNodes    = errors.New("no nodes")
+	errMaglevSize = errors.New("maglev table size must be a prime greater than 1")
+)
+
+// Placer 根据键选出负责它的节点，没有节点时返回错误
+type Placer interface {
+	Place(key string) (string, error)
+}
+
+// 键和节点名的哈希值：FNV-1a 再用 mix 打散，各节点对同一个键选出的节点相同
+func hash64(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return mix(h.Sum64())
+}
+
+// splitmix64 的终结函数，把输入的每一位扩散到整个输出
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Rendezvous 最高随机权重（HRW）哈希：对每个节点计算 hash(节点, 键)，取得分最高的节点。
+// 删除一个节点只影响原本属于它的键，增加节点只会把键移到新节点。查找是 O(节点数)
+type Rendezvous struct {
+	nodes  []string
+	hashes []uint64
+}
+
+func NewRendezvous(nodes []string) *Rendezvous {
+	r := &Rendezvous{nodes: slices.Clone(nodes)}
+	sort.Strings(r.nodes) // 得分相同时结果不依赖传入的顺序
+	for _, n := range r.nodes {
+		r.hashes = append(r.hashes, hash64(n))
+	}
+	return r
+}
+
+func (r *Rendezvous) Place(key string) (string, error) {
+	if len(r.nodes) == 0 {
+		return "", errNoNodes
+	}
+	hk := hash64(key)
+	best, bestScore := "", uint64(0)
+	for i, hn := range r.hashes {
+		if score := mix(hk ^ hn); best == "" || score > bestScore {
+			best, bestScore = r.nodes[i], score
+		}
+	}
+	return best, nil
+}
+
+// Jump 一致性哈希（Lamping & Veach, 2014）：不需要任何内存，O(log 节点数) 算出桶号。
+// 桶号只能是 0..n-1，因此只适合节点编号连续、只在末尾增删的场景（例如存储分片），
+// 删除中间的节点会让后面所有节点的编号改变
+type Jump struct {
+	nodes []string
+}
+
+func NewJump(nodes []string) *Jump {
+	return &Jump{nodes: slices.Clone(nodes)}
+}
+
+// jumpHash 返回键在 n 个桶中的桶号
+func jumpHash(key uint64, n int) int {
+	b, j := int64(-1), int64(0)
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+func (j *Jump) Place(key string) (string, error) {
+	if len(j.nodes) == 0 {
+		return "", errNoNodes
+	}
+	return j.nodes[jumpHash(hash64(key), len(j.nodes))], nil
+}
+
+// Maglev 哈希（Google Maglev 负载均衡器, 2016）：预先生成一张大小为素数 M 的查找表，查找只需一次取模。
+// 每个节点按自己的 (offset, skip) 生成 0..M-1 的一个排列，各节点轮流按自己的排列抢占表中的空位，
+// 因此每个节点占据的表项数几乎完全相同。节点变化时大部分表项不变，但不保证最小迁移
+type Maglev struct {
+	nodes []string
+	table []int32 // 表项对应的节点下标
+}
+
+// DefaultMaglevSize Maglev 查找表的默认大小，是素数，应远大于节点数
+const DefaultMaglevSize = 65537
+
+// NewMaglev 生成大小为 size 的查找表。size 必须是大于 1 的素数：
+// skip 取值于 1..size-1，只有 size 为素数时每个节点的排列才能遍历所有表项
+func NewMaglev(nodes []string, size int) (*Maglev, error) {
+	if !isPrime(size) {
+		return nil, errMaglevSize
+	}
+	m := &Maglev{nodes: slices.Clone(nodes), table: make([]int32, size)}
+	sort.Strings(m.nodes)
+	if len(nodes) == 0 {
+		return m, nil
+	}
+	M := uint64(size)
+	offsets := make([]uint64, len(m.nodes))
+	skips := make([]uint64, len(m.nodes))
+	next := make([]uint64, len(m.nodes))
+	for i, n := range m.nodes {
+		h := hash64(n)
+		offsets[i] = h % M
+		skips[i] = mix(h)%(M-1) + 1
+	}
+	for i := range m.table {
+		m.table[i] = -1
+	}
+	for filled := 0; ; {
+		for i := range m.nodes {
+			// 按排列找到节点 i 的下一个空位
+			c := (offsets[i] + next[i]*skips[i]) % M
+			for m.table[c] >= 0 {
+				next[i]++
+				c = (offsets[i] + next[i]*skips[i]) % M
+			}
+			m.table[c] = int32(i)
+			next[i]++
+			if filled++; filled == size {
+				return m, nil
+			}
+		}
+	}
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Maglev) Place(key string) (string, error) {
+	if len(m.nodes) == 0 {
+		return "", errNoNodes
+	}
+	return m.nodes[m.table[hash64(key)%uint64(len(m.table))]], nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Count-Min 草图（Cormode & Muthukrishnan, 2005）：估计每个元素出现的次数。
+// d 行计数器，每行 w 个，元素在每行中由不同的哈希函数选出一个计数器；
+// 加入时各行选中的计数器加上次数，查询时取它们的最小值。
+// 其他元素落到同一个计数器只会让它变大，所以估计值不会偏小；
+// w = ⌈e/ε⌉、d = ⌈ln(1/δ)⌉ 时，估计值超出真实值 ε·N 以上的概率不超过 δ，N 是所有次数之和。
+//
+// 保守更新（conservative update, Estan & Varghese）：加入时只把计数器提高到「当前估计值 + 次数」，
+// 已经更大的计数器不变。估计值仍然不会偏小，但误差小得多；代价是不再支持减少次数。
+// 两个草图逐个计数器相加后，每个计数器仍不小于落到它上面的元素的真实次数之和，合并结果依然有效。
+
+type CountMin struct {
+	width, depth uint64
+	conservative bool
+	total        uint64 // 所有次数之和 N
+	counters     []uint64
+}
+
+// NewCountMin 创建误差为 epsilon·N、失败概率为 delta 的 Count-Min 草图，conservative 表示是否使用保守更新。
+// epsilon 和 delta 都在 (0, 1) 内
+func NewCountMin(epsilon, delta float64, conservative bool) *CountMin {
+	if !(epsilon > 0 && epsilon < 1) || !(delta > 0 && delta < 1) {
+		panic("count-min: epsilon and delta must be in (0, 1)")
+	}
+	w := uint64(math.Ceil(math.E / epsilon))
+	d := uint64(math.Ceil(math.Log(1 / delta)))
+	return newCountMin(max(w, 1), max(d, 1), conservative)
+}
+
+func newCountMin(w, d uint64, conservative bool) *CountMin {
+	return &CountMin{width: w, depth: d, conservative: conservative, counters: make([]uint64, w*d)}
+}
+
+// 元素在各行中的计数器下标，d 个哈希函数用双重哈希 h1 + i·h2 模拟
+func (s *CountMin) cells(data []byte) []uint64 {
+	h1 := hash64(data)
+	h2 := mix(h1) | 1
+	cells := make([]uint64, s.depth)
+	for i := range cells {
+		cells[i] = uint64(i)*s.width + (h1+uint64(i)*h2)%s.width
+	}
+	return cells
+}
+
+// Add 把元素的次数加上 count，返回加入后的估计值
+func (s *CountMin) Add(data []byte, count uint64) uint64 {
+	cells := s.cells(data)
+	s.total += count
+	if !s.conservative {
+		est := uint64(math.MaxUint64)
+		for _, c := range cells {
+			s.counters[c] += count
+			est = min(est, s.counters[c])
+		}
+		return est
+	}
+	est := s.min(cells) + count
+	for _, c := range cells {
+		s.counters[c] = max(s.counters[c], est)
+	}
+	return est
+}
+
+func (s *CountMin) min(cells []uint64) uint64 {
+	est := uint64(math.MaxUint64)
+	for _, c := range cells {
+		est = min(est, s.counters[c])
+	}
+	return est
+}
+
+// Estimate 返回元素次数的估计值，不小于真实值
+func (s *CountMin) Estimate(data []byte) uint64 {
+	return s.min(s.cells(data))
+}
+
+// Total 返回所有次数之和
+func (s *CountMin) Total() uint64 {
+	return s.total
+}
+
+// Merge 把 other 合并进来，两者的宽度、深度和更新方式必须相同
+func (s *CountMin) Merge(other *CountMin) error {
+	if s.width != other.width || s.depth != other.depth || s.conservative != other.conservative {
+		return errIncompatible
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// 序列化格式：类型、宽度、深度、是否保守更新、总数，然后是各计数器
+func (s *CountMin) MarshalBinary() ([]byte, error) {
+	return s.appendBinary(nil), nil
+}
+
+func (s *CountMin) appendBinary(buf []byte) []byte {
+	buf = append(buf, kindCountMin)
+	buf = binary.AppendUvarint(buf, s.width)
+	buf = binary.AppendUvarint(buf, s.depth)
+	conservative := uint64(0)
+	if s.conservative {
+		conservative = 1
+	}
+	buf = binary.AppendUvarint(buf, conservative)
+	buf = binary.AppendUvarint(buf, s.total)
+	for _, c := range s.counters {
+		buf = binary.AppendUvarint(buf, c)
+	}
+	return buf
+}
+
+func (s *CountMin) UnmarshalBinary(data []byte) error {
+	r := reader{data: data}
+	if err := s.read(&r); err != nil {
+		return err
+	}
+	return r.done()
+}
+
+func (s *CountMin) read(r *reader) error {
+	r.kind(kindCountMin)
+	w, d, conservative, total := r.uvarint(), r.uvarint(), r.uvarint(), r.uvarint()
+	// 每个计数器至少占一个字节，据此拒绝过大的尺寸。用除法比较，w·d 可能溢出
+	if r.err != nil || w == 0 || d == 0 || conservative > 1 || w > uint64(len(r.data)) || d > uint64(len(r.data))/w {
+		return errCorrupt
+	}
+	res := newCountMin(w, d, conservative == 1)
+	res.total = total
+	for i := range res.counters {
+		res.counters[i] = r.uvarint()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	*s = *res
+	return nil
+}
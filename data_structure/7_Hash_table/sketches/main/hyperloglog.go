@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// HyperLogLog：估计集合中不同元素的个数（基数）。哈希值的前 p 位选出 m = 2^p 个寄存器中的一个，
+// 寄存器记录其余位中前导零个数加一的最大值。前导零越多的哈希值越罕见，由各寄存器的值就能估计基数，
+// 相对误差约为 1.04/√m。两个草图的寄存器逐个取最大值，就得到两个集合并集的草图。
+//
+// HLL++（Heule 等, 2013）在此基础上做了两点改进，这里都实现了：
+//   - 使用 64 位哈希，基数很大时不需要修正哈希碰撞；
+//   - 稀疏表示：元素很少时只记录出现过的寄存器，并使用更高的精度 p' = 25，
+//     用线性计数（linear counting）估计，小基数时几乎没有误差；记录数多到比稠密表示还占空间时再转为稠密表示。
+//
+// 论文中稠密表示在小基数区间用经验偏差表修正，这里换成了 Ertl（2017）的改进估计量，
+// 它直接由寄存器值的分布计算，在整个区间内都近似无偏，不需要查表。
+
+const (
+	minPrecision    = 4
+	maxPrecision    = 18
+	sparsePrecision = 25
+)
+
+type HyperLogLog struct {
+	p         uint8
+	sparse    map[uint32]uint8 // 稀疏表示：精度 p' 下的寄存器下标到寄存器值，转为稠密表示后为 nil
+	registers []uint8          // 稠密表示
+}
+
+// NewHyperLogLog 创建有 2^precision 个寄存器的草图，precision 在 4 到 18 之间
+func NewHyperLogLog(precision int) *HyperLogLog {
+	if precision < minPrecision || precision > maxPrecision {
+		panic("hyperloglog: precision out of range")
+	}
+	return &HyperLogLog{p: uint8(precision), sparse: make(map[uint32]uint8)}
+}
+
+// 哈希值在精度 p 下的寄存器下标和寄存器值；寄存器值最大为 64-p+1
+func position(h uint64, p uint8) (uint32, uint8) {
+	return uint32(h >> (64 - p)), uint8(bits.LeadingZeros64(h<<p|1<<(p-1))) + 1
+}
+
+// 稀疏表示中的一项换算到精度 p：下标取前 p 位，值要加上下标中被截去的 25-p 位的前导零
+func fromSparse(idx uint32, rho uint8, p uint8) (uint32, uint8) {
+	shift := sparsePrecision - p
+	low := idx & (1<<shift - 1)
+	if low == 0 {
+		return idx >> shift, rho + shift
+	}
+	return idx >> shift, uint8(bits.LeadingZeros32(low<<(32-shift))) + 1
+}
+
+func (h *HyperLogLog) Add(data []byte) {
+	h.AddHash(hash64(data))
+}
+
+// AddHash 加入一个已经计算好的 64 位哈希值
+func (h *HyperLogLog) AddHash(x uint64) {
+	if h.sparse != nil {
+		idx, rho := position(x, sparsePrecision)
+		if rho > h.sparse[idx] {
+			h.sparse[idx] = rho
+			h.maybeToDense()
+		}
+		return
+	}
+	idx, rho := position(x, h.p)
+	h.registers[idx] = max(h.registers[idx], rho)
+}
+
+// 稀疏表示的每一项序列化后约占 4 字节，比 m 个字节的稠密表示还大时转换
+func (h *HyperLogLog) maybeToDense() {
+	if len(h.sparse)*4 > 1<<h.p {
+		h.toDense()
+	}
+}
+
+func (h *HyperLogLog) toDense() {
+	h.registers = make([]uint8, 1<<h.p)
+	for idx, rho := range h.sparse {
+		i, r := fromSparse(idx, rho, h.p)
+		h.registers[i] = max(h.registers[i], r)
+	}
+	h.sparse = nil
+}
+
+// Count 返回基数的估计值
+func (h *HyperLogLog) Count() uint64 {
+	if h.sparse != nil {
+		m := float64(uint64(1) << sparsePrecision)
+		return uint64(math.Round(m * math.Log(m/(m-float64(len(h.sparse))))))
+	}
+	return uint64(math.Round(ertlEstimate(h.registers, int(h.p))))
+}
+
+// Ertl 改进估计量：c[k] 是值为 k 的寄存器个数，q = 64-p
+func ertlEstimate(registers []uint8, p int) float64 {
+	q := 64 - p
+	m := float64(len(registers))
+	c := make([]float64, q+2)
+	for _, r := range registers {
+		c[r]++
+	}
+	z := m * tau(1-c[q+1]/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + c[k])
+	}
+	z += m * sigma(c[0]/m)
+	return m * m / (2 * math.Ln2 * z)
+}
+
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		prev := z
+		z += x * y
+		y += y
+		if z == prev {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		prev := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if z == prev {
+			return z / 3
+		}
+	}
+}
+
+// Merge 把 other 合并进来，之后 h 是两个集合并集的草图。两者的精度必须相同
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.p != other.p {
+		return errIncompatible
+	}
+	if other.sparse != nil {
+		for idx, rho := range other.sparse {
+			if h.sparse != nil {
+				h.sparse[idx] = max(h.sparse[idx], rho)
+				continue
+			}
+			i, r := fromSparse(idx, rho, h.p)
+			h.registers[i] = max(h.registers[i], r)
+		}
+		if h.sparse != nil {
+			h.maybeToDense()
+		}
+		return nil
+	}
+	if h.sparse != nil {
+		h.toDense()
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// 序列化格式：类型、精度、表示方式，稀疏表示接着是项数和按升序排列的 下标<<6|值 的差分，稠密表示接着是各寄存器
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	buf := []byte{kindHyperLogLog, h.p}
+	if h.sparse == nil {
+		buf = append(buf, 1)
+		return append(buf, h.registers...), nil
+	}
+	buf = append(buf, 0)
+	entries := make([]uint32, 0, len(h.sparse))
+	for idx, rho := range h.sparse {
+		entries = append(entries, idx<<6|uint32(rho))
+	}
+	slices.Sort(entries)
+	buf = binary.AppendUvarint(buf, uint64(len(entries)))
+	prev := uint32(0)
+	for _, e := range entries {
+		buf = binary.AppendUvarint(buf, uint64(e-prev))
+		prev = e
+	}
+	return buf, nil
+}
+
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	r := reader{data: data}
+	r.kind(kindHyperLogLog)
+	head := r.bytes(2)
+	if r.err != nil {
+		return r.err
+	}
+	p, dense := head[0], head[1]
+	if p < minPrecision || p > maxPrecision || dense > 1 {
+		return errCorrupt
+	}
+	res := HyperLogLog{p: p}
+	if dense == 1 {
+		res.registers = slices.Clone(r.bytes(1 << p))
+		for _, v := range res.registers {
+			if v > 64-p+1 {
+				return errCorrupt
+			}
+		}
+	} else {
+		n := r.uvarint()
+		if n > 1<<p {
+			return errCorrupt
+		}
+		res.sparse = make(map[uint32]uint8, n)
+		e := uint64(0)
+		for i := uint64(0); i < n && r.err == nil; i++ {
+			e += r.uvarint()
+			idx, rho := e>>6, uint8(e&63)
+			if idx >= 1<<sparsePrecision || rho == 0 || rho > 64-sparsePrecision+1 {
+				return errCorrupt
+			}
+			res.sparse[uint32(idx)] = rho
+		}
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	*h = res
+	return nil
+}
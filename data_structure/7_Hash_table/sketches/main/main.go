@@ -0,0 +1,191 @@
+package main
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+)
+
+// 模拟按地址统计链上活动：交易分散在几个节点上处理，每个节点各自维护草图，
+// 序列化后发送到汇总节点合并，再与精确统计比较
+
+const (
+	nodes     = 4
+	events    = 1000000
+	addresses = 200000
+	topK      = 10
+)
+
+// Sketch 各种草图共同的接口
+type Sketch interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func address(i uint64) string {
+	return "0x" + strconv.FormatUint(mix(i+1)>>4, 16)
+}
+
+// 模拟节点把草图序列化发送出去，汇总节点反序列化到 dst
+func ship(src, dst Sketch) error {
+	data, err := src.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := dst.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	again, _ := dst.MarshalBinary()
+	if string(again) != string(data) {
+		return fmt.Errorf("%T changed after a round trip", src)
+	}
+	return nil
+}
+
+type nodeSketches struct {
+	distinct *HyperLogLog
+	top      *TopK
+	plain    *CountMin // 不使用保守更新，用来比较误差
+	values   *TDigest
+}
+
+func newNodeSketches() *nodeSketches {
+	return &nodeSketches{
+		distinct: NewHyperLogLog(14),
+		top:      NewTopK(topK, 0.0005, 0.01),
+		plain:    NewCountMin(0.0005, 0.01, false),
+		values:   NewTDigest(200),
+	}
+}
+
+// 反序列化另一组草图并合并进来
+func (s *nodeSketches) merge(other *nodeSketches) error {
+	var (
+		distinct HyperLogLog
+		top      TopK
+		plain    CountMin
+		values   TDigest
+	)
+	for _, p := range []struct{ src, dst Sketch }{
+		{other.distinct, &distinct}, {other.top, &top}, {other.plain, &plain}, {other.values, &values},
+	} {
+		if err := ship(p.src, p.dst); err != nil {
+			return err
+		}
+	}
+	return cmp.Or(s.distinct.Merge(&distinct), s.top.Merge(&top), s.plain.Merge(&plain), s.values.Merge(&values))
+}
+
+func main() {
+	rng := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rng, 1.1, 1, addresses-1)
+
+	exactCounts := make(map[string]uint64)
+	var exactValues []float64
+	perNode := make([]*nodeSketches, nodes)
+	for i := range perNode {
+		perNode[i] = newNodeSketches()
+	}
+	for i := 0; i < events; i++ {
+		addr := address(zipf.Uint64())
+		value := math.Exp(rng.NormFloat64()*2 + 3) // 交易金额，对数正态分布
+		s := perNode[rng.Intn(nodes)]
+		s.distinct.Add([]byte(addr))
+		s.top.Add(addr, 1)
+		s.plain.Add([]byte(addr), 1)
+		s.values.Add(value)
+		exactCounts[addr]++
+		exactValues = append(exactValues, value)
+	}
+
+	total := newNodeSketches()
+	for _, s := range perNode {
+		if err := total.merge(s); err != nil {
+			fmt.Println("合并失败：", err)
+			return
+		}
+	}
+
+	// 基数
+	est := total.distinct.Count()
+	fmt.Printf("不同地址：精确 %d，HyperLogLog 估计 %d，误差 %.2f%%（理论标准误差 %.2f%%）\n",
+		len(exactCounts), est, relErr(float64(est), float64(len(exactCounts)))*100, 104/math.Sqrt(1<<14))
+
+	// 稀疏表示在小基数时几乎没有误差，序列化后也小得多
+	small := NewHyperLogLog(14)
+	for i := uint64(0); i < 1000; i++ {
+		small.Add([]byte(address(i)))
+	}
+	data, _ := small.MarshalBinary()
+	dense, _ := total.distinct.MarshalBinary()
+	fmt.Printf("1000 个地址：估计 %d，序列化 %d 字节（稠密表示 %d 字节）\n", small.Count(), len(data), len(dense))
+
+	// 高频地址
+	type counted struct {
+		addr  string
+		count uint64
+	}
+	var exact []counted
+	for a, c := range exactCounts {
+		exact = append(exact, counted{a, c})
+	}
+	slices.SortFunc(exact, func(a, b counted) int { return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.addr, b.addr)) })
+	want := make(map[string]bool)
+	for _, e := range exact[:topK] {
+		want[e.addr] = true
+	}
+	hits := 0
+	fmt.Println("次数最多的地址（估计次数 / 精确次数）：")
+	for _, h := range total.top.Items() {
+		if want[h.Item] {
+			hits++
+		}
+		fmt.Printf("  %-18s %7d / %7d\n", h.Item, h.Count, exactCounts[h.Item])
+	}
+	fmt.Printf("前 %d 名找到 %d 个\n", topK, hits)
+
+	// 保守更新的误差
+	var plainErr, conservativeErr uint64
+	for a, c := range exactCounts {
+		plainErr += total.plain.Estimate([]byte(a)) - c
+		conservativeErr += total.top.Estimate(a) - c
+	}
+	fmt.Printf("Count-Min 平均高估：普通更新 %.2f，保守更新 %.2f（总次数 %d）\n",
+		float64(plainErr)/float64(len(exactCounts)), float64(conservativeErr)/float64(len(exactCounts)), total.plain.Total())
+
+	// 分位数
+	slices.Sort(exactValues)
+	for _, q := range []float64{0.5, 0.9, 0.99, 0.999} {
+		exactQ := exactValues[int(q*float64(len(exactValues)-1))]
+		estQ := total.values.Quantile(q)
+		fmt.Printf("金额 p%-5v 精确 %10.2f，t-digest %10.2f，误差 %.2f%%，估计的 CDF %.4f\n",
+			q*100, exactQ, estQ, relErr(estQ, exactQ)*100, total.values.CDF(exactQ))
+	}
+	values, _ := total.values.MarshalBinary()
+	fmt.Printf("t-digest 有 %d 个质心，序列化 %d 字节\n", len(total.values.centroids), len(values))
+
+	// 参数不同的草图不能合并
+	fmt.Println(NewHyperLogLog(10).Merge(NewHyperLogLog(12)))
+
+	// 损坏的编码在解码时被拒绝，而不是在解码或之后的使用中崩溃
+	digest := appendFloat64([]byte{kindTDigest}, 1e18) // compression 过大
+	digest = appendFloat64(appendFloat64(digest, 0), 0)
+	digest = binary.AppendUvarint(digest, 0)
+	fmt.Println(new(TDigest).UnmarshalBinary(digest))
+	topk := binary.AppendUvarint([]byte{kindTopK}, 10)
+	topk = append(topk, kindCountMin)
+	for _, v := range []uint64{2, 1 << 63, 1, 0} { // w·d 溢出为 0
+		topk = binary.AppendUvarint(topk, v)
+	}
+	topk = append(topk, 0, 0, 0, 0)
+	fmt.Println(new(TopK).UnmarshalBinary(topk))
+}
+
+func relErr(est, exact float64) float64 {
+	return math.Abs(est-exact) / exact
+}
@@ -0,0 +1,103 @@
+package main
+
+// 5_heap/maxheap 中的大顶堆，改为泛型并由 less 决定元素大小。
+// 把 less 取反就得到小顶堆；onMove 在元素移动到新下标时调用，使用者可以据此记录元素的位置，之后用 Fix 调整。
+
+type MaxHeap[T any] struct {
+	data   []T // 用数组存储堆元素
+	less   func(a, b T) bool
+	onMove func(x T, i int)
+}
+
+func NewMaxHeap[T any](less func(a, b T) bool, onMove func(x T, i int)) *MaxHeap[T] {
+	if onMove == nil {
+		onMove = func(T, int) {}
+	}
+	return &MaxHeap[T]{less: less, onMove: onMove}
+}
+
+// 返回父节点的索引
+func (h *MaxHeap[T]) parent(i int) int {
+	return (i - 1) / 2
+}
+
+// 返回左子节点的索引
+func (h *MaxHeap[T]) leftChild(i int) int {
+	return 2*i + 1
+}
+
+// 返回右子节点的索引
+func (h *MaxHeap[T]) rightChild(i int) int {
+	return 2*i + 2
+}
+
+func (h *MaxHeap[T]) swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+	h.onMove(h.data[i], i)
+	h.onMove(h.data[j], j)
+}
+
+func (h *MaxHeap[T]) Len() int {
+	return len(h.data)
+}
+
+// Max 返回最大元素，堆不能为空
+func (h *MaxHeap[T]) Max() T {
+	return h.data[0]
+}
+
+// 向堆中插入一个元素
+func (h *MaxHeap[T]) Insert(key T) {
+	h.data = append(h.data, key) // 将元素添加到数组末尾
+	h.onMove(key, len(h.data)-1)
+	h.siftUp(len(h.data) - 1)
+}
+
+// 从堆中删除最大元素
+func (h *MaxHeap[T]) ExtractMax() T {
+	max := h.data[0] // 最大元素为根节点
+	last := len(h.data) - 1
+	h.swap(0, last) // 将最后一个元素移到根节点
+	var zero T
+	h.data[last] = zero
+	h.data = h.data[:last] // 删除最后一个元素
+	h.maxHeapify(0)        // 从根节点开始进行堆化操作
+	return max
+}
+
+// Fix 在下标 i 的元素大小改变后恢复堆的性质
+func (h *MaxHeap[T]) Fix(i int) {
+	h.siftUp(i)
+	h.maxHeapify(i)
+}
+
+// Items 返回堆中的所有元素，顺序不定
+func (h *MaxHeap[T]) Items() []T {
+	return h.data
+}
+
+func (h *MaxHeap[T]) siftUp(i int) {
+	for i > 0 && h.less(h.data[h.parent(i)], h.data[i]) {
+		// 如果元素比父节点大，则交换它们的位置
+		h.swap(i, h.parent(i))
+		i = h.parent(i) // 更新索引
+	}
+}
+
+// 将指定的节点进行堆化操作
+func (h *MaxHeap[T]) maxHeapify(i int) {
+	left := h.leftChild(i)
+	right := h.rightChild(i)
+	largest := i
+	if left < len(h.data) && h.less(h.data[largest], h.data[left]) {
+		largest = left
+	}
+	if right < len(h.data) && h.less(h.data[largest], h.data[right]) {
+		largest = right
+	}
+	if largest != i {
+		// 如果当前节点不是最大的，则交换它和最大的子节点的位置
+		h.swap(i, largest)
+		h.maxHeapify(largest) // 递归进行堆化操作
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+)
+
+// 概率草图（sketch）：用远小于数据量的固定内存回答近似的统计问题，
+// 不同节点各自维护的草图可以序列化后发送到一处合并，结果与在全部数据上直接构建的草图相同或相近，
+// 前提是各节点把同一个元素映射到同一个寄存器或计数器。
+
+var (
+	errIncompatible = errors.New("sketches have different parameters")
+	errCorrupt      = errors.New("corrupt sketch encoding")
+)
+
+// 序列化格式的第一个字节，区分草图类型
+const (
+	kindHyperLogLog byte = iota + 1
+	kindCountMin
+	kindTopK
+	kindTDigest
+)
+
+// 数据的 64 位哈希值
+func hash64(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return mix(h.Sum64())
+}
+
+// splitmix64 的终结函数
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func appendFloat64(buf []byte, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+}
+
+// 依次读取序列化数据中的字段，遇到错误后之后的读取都返回零值，最后检查 err
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errCorrupt
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *reader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.data)) < n {
+		r.err = errCorrupt
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) float64() float64 {
+	b := r.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+// 读取类型字节
+func (r *reader) kind(kind byte) {
+	if b := r.bytes(1); b != nil && b[0] != kind {
+		r.err = errCorrupt
+	}
+}
+
+// done 检查数据是否恰好读完
+func (r *reader) done() error {
+	if r.err == nil && len(r.data) != 0 {
+		return errCorrupt
+	}
+	return r.err
+}
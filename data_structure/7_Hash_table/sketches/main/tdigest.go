@@ -0,0 +1,213 @@
+package main
+
+import (
+	"cmp"
+	"encoding/binary"
+	"math"
+	"slices"
+)
+
+// t-digest（Dunning, 2019）：估计数据的分位数。数据被聚成若干个质心（均值和权重），
+// 质心允许的最大权重由尺度函数 k(q) = δ/Z·ln(q/(1-q)) 决定（Z = 4·ln(n/δ) + 24）：相邻质心的 k 值最多相差 1，
+// 这个函数在 q 接近 0 和 1 时很陡，所以两端的质心很小，最两端只有单个数据点，
+// 尾部分位数（如 p99、p99.9）的误差远小于中位数附近。质心个数与 δ 成正比，随数据量只按对数增长。
+//
+// 这里是「合并式」实现：新数据先放进缓冲区，缓冲区满时与现有质心一起按均值排序，从左到右贪心地合并。
+// 两个 t-digest 的合并就是把对方的质心当作带权重的数据加进来。
+
+type centroid struct {
+	mean, weight float64
+}
+
+// compression 的范围。缓冲区和质心数组的大小都与它成正比，解码时也据此拒绝过大的值
+const (
+	minCompression = 1
+	maxCompression = 1e5
+)
+
+type TDigest struct {
+	compression float64 // δ
+	centroids   []centroid
+	buffer      []centroid // 还没有合并的数据
+	count       float64    // 总权重，包括缓冲区
+	min, max    float64
+}
+
+// NewTDigest 创建压缩参数为 compression 的 t-digest，取 200 时百万个数据约有 100 个质心。
+// compression 在 1 到 100000 之间
+func NewTDigest(compression float64) *TDigest {
+	if !(compression >= minCompression && compression <= maxCompression) {
+		panic("t-digest: compression out of range")
+	}
+	return &TDigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// 尺度函数的系数 δ/Z
+func (t *TDigest) normalizer() float64 {
+	return t.compression / (4*math.Log(t.count/t.compression) + 24)
+}
+
+func (t *TDigest) k(q float64) float64 {
+	return t.normalizer() * math.Log(q/(1-q))
+}
+
+func (t *TDigest) kInverse(k float64) float64 {
+	return 1 / (1 + math.Exp(-k/t.normalizer()))
+}
+
+// Add 加入一个数据点
+func (t *TDigest) Add(x float64) {
+	t.AddWeighted(x, 1)
+}
+
+// AddWeighted 加入权重为 w 的数据点
+func (t *TDigest) AddWeighted(x, w float64) {
+	t.buffer = append(t.buffer, centroid{x, w})
+	t.count += w
+	t.min = min(t.min, x)
+	t.max = max(t.max, x)
+	if len(t.buffer) >= 5*int(t.compression) {
+		t.compress()
+	}
+}
+
+// compress 把缓冲区与现有质心一起按均值排序后贪心合并
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	t.buffer = t.buffer[:0]
+	slices.SortFunc(all, func(a, b centroid) int { return cmp.Compare(a.mean, b.mean) })
+	merged := make([]centroid, 0, int(t.compression)+1)
+	cur := all[0]
+	soFar := 0.0 // 已经输出的质心的权重之和
+	limit := t.count * t.kInverse(t.k(0)+1)
+	for _, c := range all[1:] {
+		if soFar+cur.weight+c.weight <= limit {
+			// 按权重合并均值
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		soFar += cur.weight
+		merged = append(merged, cur)
+		limit = t.count * t.kInverse(t.k(soFar/t.count)+1)
+		cur = c
+	}
+	t.centroids = append(merged, cur)
+}
+
+// Count 返回总权重
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+// Quantile 返回分位数 q（0 到 1）的估计值。每个质心的均值看作位于它所覆盖的权重区间的中点，
+// 中点之间以及两端到最小值、最大值之间线性插值
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+	target := q * t.count
+	prevPos, prevVal := 0.0, t.min
+	soFar := 0.0
+	for _, c := range t.centroids {
+		pos := soFar + c.weight/2
+		if target < pos {
+			return interpolate(target, prevPos, prevVal, pos, c.mean)
+		}
+		prevPos, prevVal = pos, c.mean
+		soFar += c.weight
+	}
+	return interpolate(target, prevPos, prevVal, t.count, t.max)
+}
+
+func interpolate(x, x0, y0, x1, y1 float64) float64 {
+	if x1 <= x0 {
+		return y1
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// CDF 返回不大于 x 的数据所占比例的估计值
+func (t *TDigest) CDF(x float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+	if x < t.min {
+		return 0
+	}
+	if x >= t.max {
+		return 1
+	}
+	prevPos, prevVal := 0.0, t.min
+	soFar := 0.0
+	for _, c := range t.centroids {
+		pos := soFar + c.weight/2
+		if x < c.mean {
+			return interpolate(x, prevVal, prevPos, c.mean, pos) / t.count
+		}
+		prevPos, prevVal = pos, c.mean
+		soFar += c.weight
+	}
+	return interpolate(x, prevVal, prevPos, t.max, t.count) / t.count
+}
+
+// Merge 把 other 的质心作为带权重的数据加进来，两者的压缩参数必须相同
+func (t *TDigest) Merge(other *TDigest) error {
+	if t.compression != other.compression {
+		return errIncompatible
+	}
+	for _, c := range slices.Concat(other.centroids, other.buffer) {
+		t.AddWeighted(c.mean, c.weight)
+	}
+	t.min = min(t.min, other.min)
+	t.max = max(t.max, other.max)
+	return nil
+}
+
+// 序列化格式：类型、压缩参数、最小值、最大值、质心个数，然后是各质心的均值和权重
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+	buf := appendFloat64([]byte{kindTDigest}, t.compression)
+	buf = appendFloat64(buf, t.min)
+	buf = appendFloat64(buf, t.max)
+	buf = binary.AppendUvarint(buf, uint64(len(t.centroids)))
+	for _, c := range t.centroids {
+		buf = appendFloat64(buf, c.mean)
+		buf = appendFloat64(buf, c.weight)
+	}
+	return buf, nil
+}
+
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	r := reader{data: data}
+	r.kind(kindTDigest)
+	compression := r.float64()
+	if r.err != nil || !(compression >= minCompression && compression <= maxCompression) {
+		return errCorrupt
+	}
+	res := NewTDigest(compression)
+	res.min, res.max = r.float64(), r.float64()
+	n := r.uvarint()
+	if r.err != nil || n > uint64(len(r.data))/16 {
+		return errCorrupt
+	}
+	res.centroids = make([]centroid, n)
+	for i := range res.centroids {
+		c := centroid{r.float64(), r.float64()}
+		if !(c.weight > 0) || (i > 0 && c.mean < res.centroids[i-1].mean) {
+			return errCorrupt
+		}
+		res.centroids[i] = c
+		res.count += c.weight
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	*t = *res
+	return nil
+}
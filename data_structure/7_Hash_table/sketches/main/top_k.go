@@ -0,0 +1,143 @@
+package main
+
+import (
+	"cmp"
+	"encoding/binary"
+	"math"
+	"slices"
+)
+
+// 高频元素（heavy hitters）：Count-Min 草图只能回答给定元素的次数，不知道有哪些元素。
+// 另外用一个容量为 k 的小顶堆保存估计次数最大的 k 个候选元素，堆顶是其中次数最小的一个：
+// 每加入一个元素就用草图得到它的估计次数，已在堆中的更新位置，不在堆中且比堆顶大的替换堆顶。
+// 小顶堆由 MaxHeap 把比较反过来得到，另用一个表记录每个候选元素在堆中的下标。
+
+type HeavyHitter struct {
+	Item  string
+	Count uint64 // 估计次数
+}
+
+type TopK struct {
+	k      int
+	sketch *CountMin
+	heap   *MaxHeap[*HeavyHitter]
+	index  map[string]int // 候选元素在堆中的下标
+}
+
+// NewTopK 创建记录次数最多的 k 个元素的草图，epsilon 和 delta 是底层 Count-Min 草图的参数
+func NewTopK(k int, epsilon, delta float64) *TopK {
+	if k < 1 {
+		panic("top-k: k must be positive")
+	}
+	return newTopK(k, NewCountMin(epsilon, delta, true))
+}
+
+func newTopK(k int, sketch *CountMin) *TopK {
+	index := make(map[string]int)
+	heap := NewMaxHeap(
+		func(a, b *HeavyHitter) bool { return a.Count > b.Count },
+		func(x *HeavyHitter, i int) { index[x.Item] = i },
+	)
+	return &TopK{k: k, sketch: sketch, heap: heap, index: index}
+}
+
+// Add 把元素的次数加上 count
+func (t *TopK) Add(item string, count uint64) {
+	t.offer(item, t.sketch.Add([]byte(item), count))
+}
+
+// 用估计次数更新候选元素
+func (t *TopK) offer(item string, est uint64) {
+	if i, ok := t.index[item]; ok {
+		t.heap.Items()[i].Count = est
+		t.heap.Fix(i)
+		return
+	}
+	if t.heap.Len() == t.k {
+		if est <= t.heap.Max().Count {
+			return
+		}
+		delete(t.index, t.heap.ExtractMax().Item)
+	}
+	t.heap.Insert(&HeavyHitter{Item: item, Count: est})
+}
+
+// Estimate 返回任意元素次数的估计值
+func (t *TopK) Estimate(item string) uint64 {
+	return t.sketch.Estimate([]byte(item))
+}
+
+// Items 返回候选元素，按估计次数从大到小排列
+func (t *TopK) Items() []HeavyHitter {
+	items := make([]HeavyHitter, 0, t.heap.Len())
+	for _, h := range t.heap.Items() {
+		items = append(items, *h)
+	}
+	slices.SortFunc(items, func(a, b HeavyHitter) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Item, b.Item))
+	})
+	return items
+}
+
+// Merge 把 other 合并进来：先合并草图，再用合并后的估计次数从两边的候选元素中重新选出 k 个
+func (t *TopK) Merge(other *TopK) error {
+	if t.k != other.k {
+		return errIncompatible
+	}
+	if err := t.sketch.Merge(other.sketch); err != nil {
+		return err
+	}
+	candidates := make(map[string]bool)
+	for _, h := range t.heap.Items() {
+		candidates[h.Item] = true
+	}
+	for _, h := range other.heap.Items() {
+		candidates[h.Item] = true
+	}
+	t.rebuild(candidates)
+	return nil
+}
+
+func (t *TopK) rebuild(candidates map[string]bool) {
+	fresh := newTopK(t.k, t.sketch)
+	for item := range candidates {
+		fresh.offer(item, t.sketch.Estimate([]byte(item)))
+	}
+	*t = *fresh
+}
+
+// 序列化格式：类型、k、Count-Min 草图，然后是候选元素的个数和各元素（长度和内容），次数在反序列化时由草图重新估计
+func (t *TopK) MarshalBinary() ([]byte, error) {
+	buf := append([]byte{kindTopK}, binary.AppendUvarint(nil, uint64(t.k))...)
+	buf = t.sketch.appendBinary(buf)
+	buf = binary.AppendUvarint(buf, uint64(t.heap.Len()))
+	for _, h := range t.Items() {
+		buf = binary.AppendUvarint(buf, uint64(len(h.Item)))
+		buf = append(buf, h.Item...)
+	}
+	return buf, nil
+}
+
+func (t *TopK) UnmarshalBinary(data []byte) error {
+	r := reader{data: data}
+	r.kind(kindTopK)
+	k := r.uvarint()
+	var sketch CountMin
+	if err := sketch.read(&r); err != nil {
+		return err
+	}
+	n := r.uvarint()
+	if r.err != nil || k == 0 || k > math.MaxInt || n > k || n > uint64(len(r.data)) {
+		return errCorrupt
+	}
+	candidates := make(map[string]bool, n)
+	for i := uint64(0); i < n; i++ {
+		candidates[string(r.bytes(r.uvarint()))] = true
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	*t = TopK{k: int(k), sketch: &sketch}
+	t.rebuild(candidates)
+	return nil
+}